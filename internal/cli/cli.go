@@ -0,0 +1,257 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/danjecu/focusboard-tui/internal/model"
+	"github.com/danjecu/focusboard-tui/internal/storage"
+)
+
+// Exit codes returned by Run.
+const (
+	ExitOK       = 0
+	ExitError    = 1
+	ExitUsage    = 2
+	ExitNotFound = 3
+)
+
+type command struct {
+	name    string
+	usage   string
+	summary string
+	run     func(env *env, args []string) error
+}
+
+type env struct {
+	path   string
+	stdout io.Writer
+	stderr io.Writer
+}
+
+type usageError struct{ msg string }
+
+func (e usageError) Error() string { return e.msg }
+
+type notFoundError struct{ msg string }
+
+func (e notFoundError) Error() string { return e.msg }
+
+func usagef(format string, a ...any) error {
+	return usageError{fmt.Sprintf(format, a...)}
+}
+
+func notFoundf(format string, a ...any) error {
+	return notFoundError{fmt.Sprintf(format, a...)}
+}
+
+var commands []command
+
+func init() {
+	commands = []command{
+		{"add", "add <project> <title> [--link URL] [--due DATE] [--priority P] [--tags a,b] [--json]", "add a todo, creating the project if needed", runAdd},
+		{"list", "list [project] [--ids] [--json]", "list todos of one or all projects", runList},
+		{"done", "done [project] <index|id> [--reopen] [--subtasks] [--json]", "mark a todo completed (or reopen it)", runDone},
+		{"rm", "rm <project> [index|id] [--json]", "remove a todo, or the whole project when none is given", runRm},
+		{"projects", "projects [--json]", "list projects with todo counts", runProjects},
+		{"export", "export <format> [--project P] [--completed|--only-completed] [--columns a,b] [--events] [-o FILE]", "write the board as " + exporterNames(), runExport},
+		{"import", "import <format> <file>... [--project P] [--dry-run] [--json]", "merge todos from " + importerNames() + " files", runImport},
+		{"path", "path", "print the data file in use", runPath},
+		{"help", "help", "show this help", runHelp},
+	}
+}
+
+func lookup(name string) (command, bool) {
+	for _, c := range commands {
+		if c.name == name {
+			return c, true
+		}
+	}
+	return command{}, false
+}
+
+// Run executes a subcommand against the data file at path and returns the
+// process exit code.
+func Run(args []string, path string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		runHelp(&env{stdout: stderr}, nil)
+		return ExitUsage
+	}
+	cmd, ok := lookup(args[0])
+	if !ok {
+		fmt.Fprintf(stderr, "Error: unknown command %q (see focusboard help)\n", args[0])
+		return ExitUsage
+	}
+
+	e := &env{path: path, stdout: stdout, stderr: stderr}
+	err := cmd.run(e, args[1:])
+	if err == nil {
+		return ExitOK
+	}
+
+	var ue usageError
+	var nf notFoundError
+	switch {
+	case errors.Is(err, flag.ErrHelp):
+		return ExitOK
+	case errors.As(err, &ue):
+		fmt.Fprintf(stderr, "Error: %v\nUsage: focusboard %s\n", err, cmd.usage)
+		return ExitUsage
+	case errors.As(err, &nf):
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return ExitNotFound
+	default:
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return ExitError
+	}
+}
+
+func runHelp(e *env, _ []string) error {
+	fmt.Fprintln(e.stdout, "Usage: focusboard [--file PATH] [command] [args]")
+	fmt.Fprintln(e.stdout)
+	fmt.Fprintln(e.stdout, "Without a command the interactive board is started.")
+	fmt.Fprintf(e.stdout, "The data file is --file, else $%s, else %s in the enclosing git\n", storage.EnvFile, storage.RepoFile)
+	fmt.Fprintln(e.stdout, "repository, else $XDG_DATA_HOME/focusboard/todos.json (or ./todos.json from")
+	fmt.Fprintln(e.stdout, "older versions while that file does not exist).")
+	fmt.Fprintln(e.stdout)
+	fmt.Fprintln(e.stdout, "Commands:")
+	for _, c := range commands {
+		fmt.Fprintf(e.stdout, "  %-10s %s\n", c.name, c.summary)
+		fmt.Fprintf(e.stdout, "             focusboard %s\n", c.usage)
+	}
+	fmt.Fprintln(e.stdout)
+	fmt.Fprintf(e.stdout, "Exit codes: %d ok, %d error, %d usage, %d not found\n", ExitOK, ExitError, ExitUsage, ExitNotFound)
+	return nil
+}
+
+func newFlagSet(e *env, name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	return fs
+}
+
+// parseArgs parses flags that may appear before, between or after
+// positional arguments and returns the positionals.
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return nil, err
+			}
+			return nil, usageError{err.Error()}
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+func (e *env) load() (model.Store, error) {
+	s, err := storage.Load(e.path)
+	var corrupt *storage.CorruptError
+	if errors.As(err, &corrupt) {
+		if backup, _, berr := storage.LatestBackup(e.path); berr == nil {
+			return s, fmt.Errorf("loading %s: %w (newest valid backup: %s)", e.path, err, backup)
+		}
+	}
+	if err != nil {
+		return s, fmt.Errorf("loading %s: %w", e.path, err)
+	}
+	return s, nil
+}
+
+func (e *env) save(s model.Store) error {
+	if err := storage.Save(e.path, s); err != nil {
+		return fmt.Errorf("saving %s: %w", e.path, err)
+	}
+	return nil
+}
+
+func (e *env) writeJSON(v any) error {
+	enc := json.NewEncoder(e.stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// findProject looks a project up by name (case-insensitive) or ID.
+func findProject(s *model.Store, ref string) (int, error) {
+	for i, p := range s.Projects {
+		if strings.EqualFold(p.Name, ref) {
+			return i, nil
+		}
+	}
+	if i := s.ProjectIndex(ref); i >= 0 {
+		return i, nil
+	}
+	return -1, notFoundf("no project named %q", ref)
+}
+
+// minIDPrefix is the shortest ID prefix findTodo accepts, so that a
+// position typed without its project is not taken for an ID.
+const minIDPrefix = 4
+
+// findTodo resolves a todo by its 1-based position within project, or by
+// ID or unique ID prefix of at least minIDPrefix characters, which also
+// reaches subtasks. An empty project searches every project. It returns
+// the project index and the todo ID.
+func findTodo(s *model.Store, project, ref string) (int, string, error) {
+	if ref == "" {
+		return -1, "", usagef("empty todo reference; give a position or an id")
+	}
+	scope := -1
+	if project != "" {
+		pi, err := findProject(s, project)
+		if err != nil {
+			return -1, "", err
+		}
+		if n, err := strconv.Atoi(ref); err == nil {
+			todos := s.Projects[pi].Todos
+			if n < 1 || n > len(todos) {
+				return -1, "", notFoundf("project %q has no todo #%d", s.Projects[pi].Name, n)
+			}
+			return pi, todos[n-1].ID, nil
+		}
+		scope = pi
+	}
+
+	pi, id := -1, ""
+	exact, matches := false, 0
+	for i, p := range s.Projects {
+		if scope >= 0 && i != scope {
+			continue
+		}
+		model.Walk(p.Todos, func(t *model.Todo, _ int) {
+			switch {
+			case exact:
+			case t.ID == ref:
+				pi, id, exact = i, t.ID, true
+			case strings.HasPrefix(t.ID, ref):
+				pi, id = i, t.ID
+				matches++
+			}
+		})
+	}
+	switch {
+	case exact:
+		return pi, id, nil
+	case len(ref) < minIDPrefix:
+		if _, err := strconv.Atoi(ref); err == nil && project == "" {
+			return -1, "", usagef("%q is not an id; give the project as well to address todo #%s", ref, ref)
+		}
+		return -1, "", usagef("id prefix %q is too short; use at least %d characters", ref, minIDPrefix)
+	case matches > 1:
+		return -1, "", usagef("todo id %q is ambiguous", ref)
+	case matches == 0:
+		return -1, "", notFoundf("no todo with id %q", ref)
+	}
+	return pi, id, nil
+}
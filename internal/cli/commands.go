@@ -0,0 +1,334 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/danjecu/focusboard-tui/internal/model"
+)
+
+// listedTodo is a todo with its 1-based position in the project; subtasks
+// have no index and are addressed by ID.
+type listedTodo struct {
+	Index int `json:"index,omitempty"`
+	model.Todo
+}
+
+type listedProject struct {
+	ID    string       `json:"id"`
+	Name  string       `json:"name"`
+	Todos []listedTodo `json:"todos"`
+}
+
+type projectSummary struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Todos     int    `json:"todos"`
+	Completed int    `json:"completed"`
+}
+
+func listProject(p model.Project) listedProject {
+	lp := listedProject{ID: p.ID, Name: p.Name, Todos: make([]listedTodo, len(p.Todos))}
+	for i, t := range p.Todos {
+		lp.Todos[i] = listedTodo{Index: i + 1, Todo: t}
+	}
+	return lp
+}
+
+func runAdd(e *env, args []string) error {
+	fs := newFlagSet(e, "add")
+	link := fs.String("link", "", "link to attach")
+	due := fs.String("due", "", "due date (YYYY-MM-DD, tomorrow, +3d, fri, ...)")
+	priority := fs.String("priority", "", "priority: high, medium, low")
+	tags := fs.String("tags", "", "comma separated tags")
+	asJSON := fs.Bool("json", false, "print the created todo as JSON")
+	pos, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) != 2 {
+		return usagef("add needs a project and a title")
+	}
+	title := strings.TrimSpace(pos[1])
+	if title == "" {
+		return usagef("title must not be empty")
+	}
+
+	todo := model.NewTodo(title, time.Now())
+	todo.Link = *link
+	todo.Tags = model.ParseTags(*tags)
+	if todo.Due, err = model.ParseDue(*due, time.Now()); err != nil {
+		return usageError{err.Error()}
+	}
+	if todo.Priority, err = model.ParsePriority(*priority); err != nil {
+		return usageError{err.Error()}
+	}
+
+	s, err := e.load()
+	if err != nil {
+		return err
+	}
+	pi, err := findProject(&s, pos[0])
+	if err != nil {
+		s.Projects = append(s.Projects, model.Project{ID: model.NewID(), Name: pos[0], Todos: []model.Todo{}})
+		pi = len(s.Projects) - 1
+	}
+	p := &s.Projects[pi]
+	p.Todos = append(p.Todos, todo)
+	if err := e.save(s); err != nil {
+		return err
+	}
+
+	index := len(p.Todos)
+	if *asJSON {
+		return e.writeJSON(listedTodo{Index: index, Todo: todo})
+	}
+	fmt.Fprintf(e.stdout, "Added #%d (%s) to %s: %s\n", index, todo.ID, p.Name, todo.Title)
+	return nil
+}
+
+func runList(e *env, args []string) error {
+	fs := newFlagSet(e, "list")
+	asJSON := fs.Bool("json", false, "print JSON")
+	ids := fs.Bool("ids", false, "show todo IDs")
+	pos, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) > 1 {
+		return usagef("list takes at most one project")
+	}
+
+	s, err := e.load()
+	if err != nil {
+		return err
+	}
+	projects := s.Projects
+	if len(pos) == 1 {
+		pi, err := findProject(&s, pos[0])
+		if err != nil {
+			return err
+		}
+		projects = s.Projects[pi : pi+1]
+	}
+
+	listed := make([]listedProject, len(projects))
+	for i, p := range projects {
+		listed[i] = listProject(p)
+	}
+	if *asJSON {
+		return e.writeJSON(listed)
+	}
+
+	for i, p := range listed {
+		if i > 0 {
+			fmt.Fprintln(e.stdout)
+		}
+		fmt.Fprintln(e.stdout, p.Name)
+		if len(p.Todos) == 0 {
+			fmt.Fprintln(e.stdout, "  (no todos)")
+		}
+		for _, t := range p.Todos {
+			printTodo(e, t, 1, *ids)
+			model.Walk(t.Subtasks, func(sub *model.Todo, depth int) {
+				printTodo(e, listedTodo{Todo: *sub}, depth+2, *ids)
+			})
+		}
+	}
+	return nil
+}
+
+func printTodo(e *env, t listedTodo, depth int, ids bool) {
+	line := formatTodo(t)
+	if ids {
+		line += "  [" + t.ID + "]"
+	}
+	fmt.Fprintf(e.stdout, "%s%s\n", strings.Repeat("  ", depth), line)
+}
+
+func formatTodo(t listedTodo) string {
+	box := "[ ]"
+	if t.Completed {
+		box = "[x]"
+	}
+	number := "   -"
+	if t.Index > 0 {
+		number = fmt.Sprintf("%3d.", t.Index)
+	}
+	line := fmt.Sprintf("%s %s %s", number, box, t.Title)
+	var meta []string
+	if done, total := t.Progress(); total > 0 {
+		meta = append(meta, fmt.Sprintf("%d/%d", done, total))
+	}
+	if t.Priority != model.PriorityNone {
+		meta = append(meta, "!"+string(t.Priority))
+	}
+	if t.Due != "" {
+		meta = append(meta, "due "+t.Due)
+	}
+	for _, tag := range t.Tags {
+		meta = append(meta, "#"+tag)
+	}
+	if t.Link != "" {
+		meta = append(meta, t.Link)
+	}
+	if len(meta) > 0 {
+		line += "  (" + strings.Join(meta, ", ") + ")"
+	}
+	return line
+}
+
+func runDone(e *env, args []string) error {
+	fs := newFlagSet(e, "done")
+	reopen := fs.Bool("reopen", false, "mark the todo as not completed")
+	subtasks := fs.Bool("subtasks", false, "apply to the todo's subtasks as well")
+	asJSON := fs.Bool("json", false, "print the updated todo as JSON")
+	pos, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) < 1 || len(pos) > 2 {
+		return usagef("done needs a todo index and project, or a todo id")
+	}
+	project, ref := "", pos[0]
+	if len(pos) == 2 {
+		project, ref = pos[0], pos[1]
+	}
+
+	s, err := e.load()
+	if err != nil {
+		return err
+	}
+	pi, id, err := findTodo(&s, project, ref)
+	if err != nil {
+		return err
+	}
+	p := &s.Projects[pi]
+	t := p.Todo(id)
+	if *subtasks {
+		t.SetCompletedTree(!*reopen, time.Now())
+	} else {
+		t.SetCompleted(!*reopen, time.Now())
+	}
+	if err := e.save(s); err != nil {
+		return err
+	}
+
+	index := topIndex(p, id)
+	if *asJSON {
+		return e.writeJSON(listedTodo{Index: index, Todo: *t})
+	}
+	verb := "Completed"
+	if *reopen {
+		verb = "Reopened"
+	}
+	fmt.Fprintf(e.stdout, "%s %s in %s: %s\n", verb, todoLabel(index, id), p.Name, t.Title)
+	return nil
+}
+
+func runRm(e *env, args []string) error {
+	fs := newFlagSet(e, "rm")
+	asJSON := fs.Bool("json", false, "print what was removed as JSON")
+	pos, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) < 1 || len(pos) > 2 {
+		return usagef("rm needs a project and optionally a todo index or id")
+	}
+
+	s, err := e.load()
+	if err != nil {
+		return err
+	}
+
+	if len(pos) == 1 {
+		pi, err := findProject(&s, pos[0])
+		if err != nil {
+			return err
+		}
+		removed, _ := s.RemoveProject(s.Projects[pi].ID)
+		if err := e.save(s); err != nil {
+			return err
+		}
+		if *asJSON {
+			return e.writeJSON(listProject(removed))
+		}
+		fmt.Fprintf(e.stdout, "Removed project %s (%d todos)\n", removed.Name, model.CountTodos(removed.Todos))
+		return nil
+	}
+
+	pi, id, err := findTodo(&s, pos[0], pos[1])
+	if err != nil {
+		return err
+	}
+	p := &s.Projects[pi]
+	index := topIndex(p, id)
+	removed, _ := p.RemoveTodo(id)
+	if err := e.save(s); err != nil {
+		return err
+	}
+	if *asJSON {
+		return e.writeJSON(listedTodo{Index: index, Todo: removed})
+	}
+	fmt.Fprintf(e.stdout, "Removed %s from %s: %s\n", todoLabel(index, id), p.Name, removed.Title)
+	return nil
+}
+
+// topIndex returns the 1-based position of a top-level todo, or 0 for a
+// subtask.
+func topIndex(p *model.Project, id string) int {
+	if _, i, parent := p.Locate(id); parent == nil && i >= 0 {
+		return i + 1
+	}
+	return 0
+}
+
+func todoLabel(index int, id string) string {
+	if index > 0 {
+		return fmt.Sprintf("#%d", index)
+	}
+	return "subtask " + id
+}
+
+func runPath(e *env, args []string) error {
+	if len(args) != 0 {
+		return usagef("path takes no arguments")
+	}
+	fmt.Fprintln(e.stdout, e.path)
+	return nil
+}
+
+func runProjects(e *env, args []string) error {
+	fs := newFlagSet(e, "projects")
+	asJSON := fs.Bool("json", false, "print JSON")
+	pos, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) != 0 {
+		return usagef("projects takes no arguments")
+	}
+
+	s, err := e.load()
+	if err != nil {
+		return err
+	}
+	summaries := make([]projectSummary, len(s.Projects))
+	for i, p := range s.Projects {
+		summaries[i] = projectSummary{ID: p.ID, Name: p.Name, Todos: model.CountTodos(p.Todos)}
+		model.Walk(p.Todos, func(t *model.Todo, _ int) {
+			if t.Completed {
+				summaries[i].Completed++
+			}
+		})
+	}
+	if *asJSON {
+		return e.writeJSON(summaries)
+	}
+	for _, p := range summaries {
+		fmt.Fprintf(e.stdout, "%s (%d/%d done)\n", p.Name, p.Completed, p.Todos)
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/danjecu/focusboard-tui/internal/format"
+	"github.com/danjecu/focusboard-tui/internal/model"
+)
+
+type exporter struct {
+	name    string
+	write   func(w io.Writer, projects []model.Project, opts format.Options) error
+	columns bool
+}
+
+var exporters = []exporter{
+	{"markdown", format.WriteMarkdown, false},
+	{"todotxt", format.WriteTodoTxt, false},
+	{"csv", format.WriteCSV, true},
+	{"jsonl", format.WriteJSONL, true},
+	{"org", format.WriteOrg, false},
+	{"ics", format.WriteICal, false},
+	{"taskwarrior", format.WriteTaskwarrior, false},
+}
+
+func exporterNames() string {
+	names := make([]string, len(exporters))
+	for i, x := range exporters {
+		names[i] = x.name
+	}
+	return strings.Join(names, ", ")
+}
+
+func runExport(e *env, args []string) error {
+	fs := newFlagSet(e, "export")
+	project := fs.String("project", "", "export only this project (name or id)")
+	completed := fs.Bool("completed", false, "include completed todos")
+	onlyCompleted := fs.Bool("only-completed", false, "export completed todos only")
+	columns := fs.String("columns", "", "comma separated columns for csv and jsonl: "+strings.Join(format.ColumnNames(), ","))
+	events := fs.Bool("events", false, "ics: also add an all-day event on each due date")
+	out := fs.String("o", "", "write to this file instead of stdout")
+	pos, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) != 1 {
+		return usagef("export needs a format: %s", exporterNames())
+	}
+	var exp *exporter
+	for i := range exporters {
+		if exporters[i].name == pos[0] {
+			exp = &exporters[i]
+		}
+	}
+	if exp == nil {
+		return usagef("unknown export format %q (choose from %s)", pos[0], exporterNames())
+	}
+
+	opts := format.Options{Completed: *completed, OnlyCompleted: *onlyCompleted, Events: *events}
+	if *events && exp.name != "ics" {
+		return usagef("--events only applies to ics")
+	}
+	if *columns != "" {
+		if !exp.columns {
+			return usagef("--columns only applies to csv and jsonl")
+		}
+		for _, c := range strings.Split(*columns, ",") {
+			if c = strings.TrimSpace(c); c != "" {
+				opts.Columns = append(opts.Columns, c)
+			}
+		}
+		if err := format.CheckColumns(opts.Columns); err != nil {
+			return usageError{err.Error()}
+		}
+	}
+
+	s, err := e.load()
+	if err != nil {
+		return err
+	}
+	projects := s.Projects
+	if *project != "" {
+		pi, err := findProject(&s, *project)
+		if err != nil {
+			return err
+		}
+		projects = s.Projects[pi : pi+1]
+	}
+
+	if *out == "" {
+		return exp.write(e.stdout, projects, opts)
+	}
+	var buf bytes.Buffer
+	if err := exp.write(&buf, projects, opts); err != nil {
+		return err
+	}
+	if err := os.WriteFile(*out, buf.Bytes(), 0o644); err != nil {
+		return err
+	}
+	fmt.Fprintf(e.stdout, "Exported %s to %s\n", exp.name, *out)
+	return nil
+}
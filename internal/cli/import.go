@@ -0,0 +1,146 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/danjecu/focusboard-tui/internal/format"
+	"github.com/danjecu/focusboard-tui/internal/model"
+)
+
+type importer struct {
+	name string
+	read func(r io.Reader, defaultProject string) ([]model.Project, error)
+}
+
+var importers = []importer{
+	{"markdown", format.ReadMarkdown},
+	{"todotxt", format.ReadTodoTxt},
+	{"org", format.ReadOrg},
+	{"taskwarrior", format.ReadTaskwarrior},
+}
+
+func importerNames() string {
+	names := make([]string, len(importers))
+	for i, x := range importers {
+		names[i] = x.name
+	}
+	return strings.Join(names, ", ")
+}
+
+type importedProject struct {
+	Name    string       `json:"name"`
+	Created bool         `json:"created"`
+	Added   []model.Todo `json:"added"`
+	Skipped []string     `json:"skipped,omitempty"`
+}
+
+type importResult struct {
+	DryRun   bool              `json:"dry_run"`
+	Projects []importedProject `json:"projects"`
+}
+
+func runImport(e *env, args []string) error {
+	fs := newFlagSet(e, "import")
+	project := fs.String("project", "", "put every imported todo into this project")
+	dryRun := fs.Bool("dry-run", false, "show what would be created without saving")
+	asJSON := fs.Bool("json", false, "print the result as JSON")
+	pos, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) < 2 {
+		return usagef("import needs a format (%s) and at least one file", importerNames())
+	}
+	var imp *importer
+	for i := range importers {
+		if importers[i].name == pos[0] {
+			imp = &importers[i]
+		}
+	}
+	if imp == nil {
+		return usagef("unknown import format %q (choose from %s)", pos[0], importerNames())
+	}
+
+	var projects []model.Project
+	for _, path := range pos[1:] {
+		ps, err := readImport(imp, path)
+		if err != nil {
+			return err
+		}
+		projects = append(projects, ps...)
+	}
+	if *project != "" {
+		merged := model.Project{Name: *project}
+		for _, p := range projects {
+			merged.Todos = append(merged.Todos, p.Todos...)
+		}
+		projects = []model.Project{merged}
+	}
+
+	s, err := e.load()
+	if err != nil {
+		return err
+	}
+	merged := s.Merge(projects)
+	if !*dryRun {
+		if err := e.save(s); err != nil {
+			return err
+		}
+	}
+
+	result := importResult{DryRun: *dryRun, Projects: make([]importedProject, len(merged))}
+	for i, mp := range merged {
+		result.Projects[i] = importedProject{Name: mp.Name, Created: mp.Created, Added: mp.Added, Skipped: mp.Skipped}
+	}
+	if *asJSON {
+		return e.writeJSON(result)
+	}
+	printImport(e, result)
+	return nil
+}
+
+func readImport(imp *importer, path string) ([]model.Project, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	projects, err := imp.read(f, name)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return projects, nil
+}
+
+func printImport(e *env, r importResult) {
+	verb := "added"
+	if r.DryRun {
+		verb = "would add"
+	}
+	total := 0
+	for _, p := range r.Projects {
+		n := model.CountTodos(p.Added)
+		total += n
+		name := p.Name
+		if p.Created {
+			name += " (new project)"
+		}
+		fmt.Fprintf(e.stdout, "%s: %s %d todos\n", name, verb, n)
+		model.Walk(p.Added, func(t *model.Todo, depth int) {
+			printTodo(e, listedTodo{Todo: *t}, depth+1, false)
+		})
+		for _, title := range p.Skipped {
+			fmt.Fprintf(e.stdout, "  skipped, already present: %s\n", title)
+		}
+	}
+	if r.DryRun {
+		fmt.Fprintf(e.stdout, "Dry run: %d todos not imported\n", total)
+	} else {
+		fmt.Fprintf(e.stdout, "Imported %d todos into %s\n", total, e.path)
+	}
+}
@@ -0,0 +1,24 @@
+package format
+
+import "github.com/danjecu/focusboard-tui/internal/model"
+
+// Options narrows what an exporter writes.
+type Options struct {
+	// Completed includes completed todos. Otherwise they are skipped, and
+	// exports that keep the todo tree skip their subtasks with them.
+	Completed bool
+	// OnlyCompleted skips every open todo instead.
+	OnlyCompleted bool
+	// Columns picks and orders the fields of tabular exports; empty means
+	// all of them.
+	Columns []string
+	// Events adds calendar events on due dates to iCalendar exports.
+	Events bool
+}
+
+func (o Options) keep(t model.Todo) bool {
+	if o.OnlyCompleted {
+		return t.Completed
+	}
+	return o.Completed || !t.Completed
+}
@@ -0,0 +1,101 @@
+package format
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/danjecu/focusboard-tui/internal/model"
+)
+
+// localDay returns local midnight, which every format can carry exactly.
+func localDay(year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, 0, 0, 0, 0, time.Local)
+}
+
+// sampleBoard exercises what trips up text formats: markup and syntax of
+// the various formats in titles and notes, links, metadata on every
+// level, and subtasks nested below todos that have notes.
+func sampleBoard() []model.Project {
+	created := localDay(2025, 6, 1)
+	return []model.Project{
+		{
+			ID:   "p1",
+			Name: "My_Proj Two",
+			Todos: []model.Todo{
+				{
+					ID:        "a1b2c3d4e5f6",
+					Title:     "Fix issue #login flow [urgent] *now*",
+					Link:      "https://example.com/issue?id=7&tab=2",
+					Priority:  model.PriorityHigh,
+					Tags:      []string{"bug", "ui"},
+					Due:       "2025-07-01",
+					Notes:     "Steps:\n- [ ] not a subtask\n# not a heading\n\\\\server\\share\n\n```\n- [ ] code stays\n```\nDone when green.",
+					CreatedAt: created,
+					UpdatedAt: created,
+					Subtasks: []model.Todo{
+						{
+							ID:          "b1b2c3d4e5f6",
+							Title:       "Email @bob about +release plan",
+							Completed:   true,
+							Priority:    model.PriorityLow,
+							CreatedAt:   created,
+							UpdatedAt:   localDay(2025, 6, 3),
+							CompletedAt: localDay(2025, 6, 3),
+						},
+						{
+							ID:        "c1b2c3d4e5f6",
+							Title:     "x marks the spot due:friday link:none",
+							Collapsed: true,
+							CreatedAt: created,
+							UpdatedAt: created,
+							Subtasks: []model.Todo{
+								{ID: "d1b2c3d4e5f6", Title: "(B) 2025-01-01 leaf", Notes: "leaf note", CreatedAt: created, UpdatedAt: created},
+							},
+						},
+					},
+				},
+				{
+					ID:          "e1b2c3d4e5f6",
+					Title:       "Ship it",
+					Completed:   true,
+					Tags:        []string{"release"},
+					CreatedAt:   created,
+					UpdatedAt:   localDay(2025, 6, 5),
+					CompletedAt: localDay(2025, 6, 5),
+				},
+			},
+		},
+		{
+			ID:   "p2",
+			Name: "Home",
+			Todos: []model.Todo{
+				{ID: "f1b2c3d4e5f6", Title: "Buy milk", Priority: model.PriorityMedium, CreatedAt: created, UpdatedAt: created},
+			},
+		},
+	}
+}
+
+// strip clears what a format does not carry from a copy of projects, so
+// the result can be compared with what reading the format gives back.
+func strip(projects []model.Project, clear func(t *model.Todo)) []model.Project {
+	out := make([]model.Project, len(projects))
+	for i, p := range projects {
+		p = p.Clone()
+		p.ID = ""
+		p.Sort = ""
+		model.Walk(p.Todos, func(t *model.Todo, _ int) { clear(t) })
+		out[i] = p
+	}
+	return out
+}
+
+func assertProjects(t *testing.T, got, want []model.Project) {
+	t.Helper()
+	g, _ := json.MarshalIndent(got, "", "  ")
+	w, _ := json.MarshalIndent(want, "", "  ")
+	if !bytes.Equal(g, w) {
+		t.Errorf("round trip mismatch\ngot:\n%s\nwant:\n%s", g, w)
+	}
+}
@@ -0,0 +1,187 @@
+package format
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/danjecu/focusboard-tui/internal/model"
+)
+
+const (
+	icalProdID   = "-//focusboard//focusboard-tui//EN"
+	icalDomain   = "focusboard"
+	icalStamp    = "20060102T150405Z"
+	icalDate     = "20060102"
+	icalLineSize = 75
+)
+
+var icalPriority = map[model.Priority]string{
+	model.PriorityHigh:   "1",
+	model.PriorityMedium: "5",
+	model.PriorityLow:    "9",
+}
+
+// WriteICal writes an iCalendar (RFC 5545) calendar with a VTODO per todo.
+// UIDs are derived from todo IDs, so calendar apps that subscribe to the
+// file see updates instead of duplicates. With Options.Events, dated todos
+// also get an all-day VEVENT on their due date.
+func WriteICal(w io.Writer, projects []model.Project, opts Options) error {
+	bw := bufio.NewWriter(w)
+	line := func(name, value string) {
+		writeICalLine(bw, name+":"+value)
+	}
+
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", icalProdID)
+	line("CALSCALE", "GREGORIAN")
+	name := "FocusBoard"
+	if len(projects) == 1 {
+		name = projects[0].Name
+	}
+	line("X-WR-CALNAME", icalText(name))
+
+	for _, p := range projects {
+		var write func(todos []model.Todo, parent string)
+		write = func(todos []model.Todo, parent string) {
+			for _, t := range todos {
+				if !opts.keep(t) {
+					continue
+				}
+				writeVTodo(line, p.Name, t, parent)
+				if opts.Events {
+					writeVEvent(line, p.Name, t)
+				}
+				write(t.Subtasks, t.ID)
+			}
+		}
+		write(p.Todos, "")
+	}
+
+	line("END", "VCALENDAR")
+	return bw.Flush()
+}
+
+func writeVTodo(line func(name, value string), project string, t model.Todo, parent string) {
+	line("BEGIN", "VTODO")
+	line("UID", icalUID(t.ID))
+	line("DTSTAMP", icalTime(dtstamp(t)))
+	line("SUMMARY", icalText(t.Title))
+	if t.Notes != "" {
+		line("DESCRIPTION", icalText(t.Notes))
+	}
+	if t.Link != "" {
+		line("URL", t.Link)
+	}
+	if due, ok := dueDate(t); ok {
+		line("DUE;VALUE=DATE", due.Format(icalDate))
+	}
+	if pri := icalPriority[t.Priority]; pri != "" {
+		line("PRIORITY", pri)
+	}
+	line("CATEGORIES", icalCategories(project, t.Tags))
+	if t.Completed {
+		line("STATUS", "COMPLETED")
+		if !t.CompletedAt.IsZero() {
+			line("COMPLETED", icalTime(t.CompletedAt))
+		}
+		line("PERCENT-COMPLETE", "100")
+	} else {
+		line("STATUS", "NEEDS-ACTION")
+	}
+	if !t.CreatedAt.IsZero() {
+		line("CREATED", icalTime(t.CreatedAt))
+	}
+	if !t.UpdatedAt.IsZero() {
+		line("LAST-MODIFIED", icalTime(t.UpdatedAt))
+	}
+	if parent != "" {
+		line("RELATED-TO;RELTYPE=PARENT", icalUID(parent))
+	}
+	line("END", "VTODO")
+}
+
+func writeVEvent(line func(name, value string), project string, t model.Todo) {
+	due, ok := dueDate(t)
+	if !ok {
+		return
+	}
+	line("BEGIN", "VEVENT")
+	line("UID", icalUID(t.ID+"-due"))
+	line("DTSTAMP", icalTime(dtstamp(t)))
+	line("DTSTART;VALUE=DATE", due.Format(icalDate))
+	line("DTEND;VALUE=DATE", due.AddDate(0, 0, 1).Format(icalDate))
+	line("SUMMARY", icalText(t.Title))
+	if t.Link != "" {
+		line("URL", t.Link)
+	}
+	line("CATEGORIES", icalCategories(project, t.Tags))
+	line("TRANSP", "TRANSPARENT")
+	line("RELATED-TO", icalUID(t.ID))
+	line("END", "VEVENT")
+}
+
+// dtstamp picks a DTSTAMP that only changes when the todo does, so exporting
+// an unchanged board gives an identical file.
+func dtstamp(t model.Todo) time.Time {
+	if !t.UpdatedAt.IsZero() {
+		return t.UpdatedAt
+	}
+	if !t.CreatedAt.IsZero() {
+		return t.CreatedAt
+	}
+	return time.Unix(0, 0)
+}
+
+func icalUID(id string) string {
+	return id + "@" + icalDomain
+}
+
+func icalTime(t time.Time) string {
+	return t.UTC().Format(icalStamp)
+}
+
+func dueDate(t model.Todo) (time.Time, bool) {
+	if t.Due == "" {
+		return time.Time{}, false
+	}
+	d, err := time.Parse(model.DateLayout, t.Due)
+	return d, err == nil
+}
+
+func icalCategories(project string, tags []string) string {
+	cats := []string{icalText(project)}
+	for _, tag := range tags {
+		cats = append(cats, icalText(tag))
+	}
+	return strings.Join(cats, ",")
+}
+
+func icalText(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(s)
+}
+
+// writeICalLine ends a content line with CRLF, folding it so no physical
+// line exceeds 75 octets without splitting a UTF-8 sequence.
+func writeICalLine(w *bufio.Writer, s string) {
+	limit := icalLineSize
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		w.WriteString(s[:cut] + "\r\n ")
+		s = s[cut:]
+		limit = icalLineSize - 1
+	}
+	w.WriteString(s + "\r\n")
+}
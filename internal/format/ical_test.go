@@ -0,0 +1,107 @@
+package format
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/danjecu/focusboard-tui/internal/model"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files in testdata")
+
+// golden compares got with testdata/name, or rewrites it with -update.
+func golden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("output differs from %s (run go test -update to accept):\n%s", path, got)
+	}
+}
+
+// utcBoard is sampleBoard with its timestamps moved to UTC at the same
+// wall clock time, so the golden files do not depend on the time zone.
+func utcBoard() []model.Project {
+	board := sampleBoard()
+	utc := func(at time.Time) time.Time {
+		if at.IsZero() {
+			return at
+		}
+		return time.Date(at.Year(), at.Month(), at.Day(), at.Hour(), at.Minute(), at.Second(), 0, time.UTC)
+	}
+	for i := range board {
+		model.Walk(board[i].Todos, func(t *model.Todo, _ int) {
+			t.CreatedAt, t.UpdatedAt, t.CompletedAt = utc(t.CreatedAt), utc(t.UpdatedAt), utc(t.CompletedAt)
+		})
+	}
+	return board
+}
+
+func TestWriteICalGolden(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		opts Options
+	}{
+		{"board.ics", Options{}},
+		{"board-events.ics", Options{Completed: true, Events: true}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := WriteICal(&buf, utcBoard(), tc.opts); err != nil {
+				t.Fatal(err)
+			}
+			golden(t, tc.name, buf.Bytes())
+		})
+	}
+}
+
+func TestWriteICalIsStable(t *testing.T) {
+	var first, second bytes.Buffer
+	WriteICal(&first, utcBoard(), Options{Completed: true, Events: true})
+	WriteICal(&second, utcBoard(), Options{Completed: true, Events: true})
+	if !bytes.Equal(first.Bytes(), second.Bytes()) {
+		t.Error("exporting the same board twice gave different files")
+	}
+}
+
+func TestICalLineFolding(t *testing.T) {
+	var buf bytes.Buffer
+	board := []model.Project{{Name: "P", Todos: []model.Todo{
+		{ID: "long", Title: strings.Repeat("héllo, wörld; ", 20)},
+	}}}
+	if err := WriteICal(&buf, board, Options{}); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	if !strings.HasSuffix(out, "\r\n") || strings.Contains(strings.ReplaceAll(out, "\r\n", ""), "\n") {
+		t.Fatal("lines must end in CRLF")
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n") {
+		if len(line) > icalLineSize {
+			t.Errorf("line of %d octets: %q", len(line), line)
+		}
+		if !utf8.ValidString(line) {
+			t.Errorf("fold split a UTF-8 sequence: %q", line)
+		}
+	}
+
+	unfolded := strings.ReplaceAll(out, "\r\n ", "")
+	want := "SUMMARY:" + strings.Repeat(`héllo\, wörld\; `, 20) + "\r\n"
+	if !strings.Contains(unfolded, want) {
+		t.Errorf("unfolded output lacks the escaped summary:\n%s", unfolded)
+	}
+}
@@ -0,0 +1,309 @@
+package format
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/danjecu/focusboard-tui/internal/model"
+)
+
+// Markers for todo metadata in markdown task items, as used by the
+// Obsidian Tasks plugin so exported files stay useful in other tools.
+const (
+	markDue       = "📅"
+	markDone      = "✅"
+	markHigh      = "⏫"
+	markMedium    = "🔼"
+	markLow       = "🔽"
+	markdownEsc   = "\\[]*`#" + markDue + markDone + markHigh + markMedium + markLow
+	markdownInset = "  "
+)
+
+// WriteMarkdown writes projects as GitHub-flavoured markdown: a heading
+// per project and a task list item per todo, with subtasks nested below
+// their parent and notes indented under the item. Note lines outside code
+// fences that would read back as task items or headings get a leading
+// backslash.
+func WriteMarkdown(w io.Writer, projects []model.Project, opts Options) error {
+	bw := bufio.NewWriter(w)
+	for i, p := range projects {
+		if i > 0 {
+			bw.WriteString("\n")
+		}
+		fmt.Fprintf(bw, "# %s\n", p.Name)
+		if hasKept(p.Todos, opts) {
+			bw.WriteString("\n")
+		}
+		writeMarkdownTodos(bw, p.Todos, p.Sort, 0, opts)
+	}
+	return bw.Flush()
+}
+
+func hasKept(todos []model.Todo, opts Options) bool {
+	for _, t := range todos {
+		if opts.keep(t) {
+			return true
+		}
+	}
+	return false
+}
+
+func writeMarkdownTodos(w *bufio.Writer, todos []model.Todo, sort model.SortMode, depth int, opts Options) {
+	indent := strings.Repeat(markdownInset, depth)
+	for _, i := range model.SortedIndices(todos, sort) {
+		t := todos[i]
+		if !opts.keep(t) {
+			continue
+		}
+		w.WriteString(indent + markdownItem(t) + "\n")
+		if t.Notes != "" {
+			inCode := false
+			for _, line := range strings.Split(t.Notes, "\n") {
+				if line == "" {
+					w.WriteString("\n")
+					continue
+				}
+				if isFence(line) {
+					inCode = !inCode
+				} else if !inCode {
+					line = escapeNoteLine(line)
+				}
+				w.WriteString(indent + markdownInset + line + "\n")
+			}
+		}
+		writeMarkdownTodos(w, t.Subtasks, sort, depth+1, opts)
+	}
+}
+
+func markdownItem(t model.Todo) string {
+	box := "[ ]"
+	if t.Completed {
+		box = "[x]"
+	}
+	title := escapeMarkdown(t.Title)
+	if t.Link != "" {
+		title = "[" + title + "](" + t.Link + ")"
+	}
+	parts := []string{"-", box, title}
+	switch t.Priority {
+	case model.PriorityHigh:
+		parts = append(parts, markHigh)
+	case model.PriorityMedium:
+		parts = append(parts, markMedium)
+	case model.PriorityLow:
+		parts = append(parts, markLow)
+	}
+	for _, tag := range t.Tags {
+		parts = append(parts, "#"+tag)
+	}
+	if t.Due != "" {
+		parts = append(parts, markDue, t.Due)
+	}
+	if t.Completed && !t.CompletedAt.IsZero() {
+		parts = append(parts, markDone, t.CompletedAt.Local().Format(model.DateLayout))
+	}
+	return strings.Join(parts, " ")
+}
+
+// escapeNoteLine puts a backslash before note lines that ReadMarkdown
+// would otherwise take for a task item or a heading, and before lines
+// already starting with one so that unescapeNoteLine can strip it again.
+func escapeNoteLine(line string) string {
+	body := strings.TrimLeft(line, " ")
+	if mdTask.MatchString(body) || mdHeading.MatchString(body) || strings.HasPrefix(body, "\\") {
+		return line[:len(line)-len(body)] + "\\" + body
+	}
+	return line
+}
+
+func unescapeNoteLine(line string) string {
+	body := strings.TrimLeft(line, " ")
+	if strings.HasPrefix(body, "\\") {
+		return line[:len(line)-len(body)] + body[1:]
+	}
+	return line
+}
+
+func isFence(line string) bool {
+	return strings.HasPrefix(strings.TrimSpace(line), "```")
+}
+
+func escapeMarkdown(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if strings.ContainsRune(markdownEsc, r) {
+			b.WriteRune('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+var (
+	mdHeading  = regexp.MustCompile(`^#{1,6}\s+(.*?)\s*#*\s*$`)
+	mdTask     = regexp.MustCompile(`^(\s*)(?:[-*+]|\d+[.)])\s+\[([ xX])\]\s+(.*)$`)
+	mdLink     = regexp.MustCompile(`\[((?:\\.|[^\]\\])*)\]\(([^)\s]+)\)`)
+	mdEscape   = regexp.MustCompile(`\\([\\\[\]*_` + "`" + `#` + markDue + markDone + markHigh + markMedium + markLow + `])`)
+	mdTagChars = regexp.MustCompile(`^#[\pL][\pL\pN_/-]*$`)
+)
+
+type mdNode struct {
+	todo     model.Todo
+	indent   int
+	notes    []string
+	children []*mdNode
+}
+
+// ReadMarkdown collects the task list items of a markdown document.
+// Headings start projects, items before the first heading go to
+// defaultProject, nested items become subtasks and other text indented
+// under an item becomes its notes. The first inline link of an item is
+// used as its link, and the markers written by WriteMarkdown are read
+// back. Headings without any task items are dropped.
+func ReadMarkdown(r io.Reader, defaultProject string) ([]model.Project, error) {
+	now := time.Now()
+	type section struct {
+		name  string
+		roots []*mdNode
+	}
+	sections := []*section{{name: defaultProject}}
+	var stack []*mdNode
+	inCode := false
+
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	for sc.Scan() {
+		line := strings.ReplaceAll(strings.TrimRight(sc.Text(), " \t\r"), "\t", "    ")
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+
+		if isFence(line) {
+			inCode = !inCode
+		} else if !inCode {
+			if m := mdHeading.FindStringSubmatch(line); m != nil {
+				sections = append(sections, &section{name: unescapeMarkdown(m[1])})
+				stack = nil
+				continue
+			}
+			if m := mdTask.FindStringSubmatch(line); m != nil {
+				node := &mdNode{todo: parseMarkdownItem(m[3], m[2] != " ", now), indent: len(m[1])}
+				for len(stack) > 0 && stack[len(stack)-1].indent >= node.indent {
+					stack = stack[:len(stack)-1]
+				}
+				if len(stack) == 0 {
+					sec := sections[len(sections)-1]
+					sec.roots = append(sec.roots, node)
+				} else {
+					top := stack[len(stack)-1]
+					top.children = append(top.children, node)
+				}
+				stack = append(stack, node)
+				continue
+			}
+		}
+
+		// Text belongs to the innermost item indented less than it, so a
+		// line back at a subtask's indent continues the parent's notes.
+		// Unindented text ends the list.
+		if line != "" && (!inCode || isFence(line)) {
+			for len(stack) > 0 && stack[len(stack)-1].indent >= indent {
+				stack = stack[:len(stack)-1]
+			}
+		}
+		if len(stack) == 0 {
+			continue
+		}
+		top := stack[len(stack)-1]
+		if line == "" {
+			top.notes = append(top.notes, "")
+			continue
+		}
+		cut := min(indent, top.indent+len(markdownInset))
+		note := line[cut:]
+		if !inCode {
+			note = unescapeNoteLine(note)
+		}
+		top.notes = append(top.notes, note)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+
+	var projects []model.Project
+	for _, sec := range sections {
+		if len(sec.roots) == 0 {
+			continue
+		}
+		projects = append(projects, model.Project{ID: model.NewID(), Name: sec.name, Todos: mdTodos(sec.roots)})
+	}
+	return projects, nil
+}
+
+func mdTodos(nodes []*mdNode) []model.Todo {
+	todos := make([]model.Todo, len(nodes))
+	for i, n := range nodes {
+		t := n.todo
+		t.Notes = strings.Trim(strings.Join(n.notes, "\n"), "\n")
+		if len(n.children) > 0 {
+			t.Subtasks = mdTodos(n.children)
+		}
+		todos[i] = t
+	}
+	return todos
+}
+
+func parseMarkdownItem(text string, done bool, now time.Time) model.Todo {
+	t := model.NewTodo("", now)
+	var completedAt time.Time
+	var words []string
+	fields := strings.Fields(text)
+	for i := 0; i < len(fields); i++ {
+		f := fields[i]
+		switch {
+		case f == markHigh:
+			t.Priority = model.PriorityHigh
+		case f == markMedium:
+			t.Priority = model.PriorityMedium
+		case f == markLow:
+			t.Priority = model.PriorityLow
+		case (f == markDue || f == markDone) && i+1 < len(fields) && isDate(fields[i+1]):
+			if f == markDue {
+				t.Due = fields[i+1]
+			} else {
+				completedAt, _ = time.ParseInLocation(model.DateLayout, fields[i+1], time.Local)
+			}
+			i++
+		case mdTagChars.MatchString(f):
+			t.Tags = append(t.Tags, model.ParseTags(f)...)
+		default:
+			words = append(words, f)
+		}
+	}
+
+	title := strings.Join(words, " ")
+	if m := mdLink.FindStringSubmatchIndex(title); m != nil {
+		t.Link = title[m[4]:m[5]]
+		title = title[:m[0]] + title[m[2]:m[3]] + title[m[1]:]
+	}
+	t.Title = unescapeMarkdown(strings.TrimSpace(title))
+	if done {
+		if completedAt.IsZero() {
+			completedAt = now
+		}
+		t.SetCompleted(true, completedAt)
+		t.UpdatedAt = now
+	}
+	return t
+}
+
+func isDate(s string) bool {
+	_, err := time.Parse(model.DateLayout, s)
+	return err == nil
+}
+
+func unescapeMarkdown(s string) string {
+	return mdEscape.ReplaceAllString(s, "$1")
+}
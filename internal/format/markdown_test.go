@@ -0,0 +1,87 @@
+package format
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/danjecu/focusboard-tui/internal/model"
+)
+
+func TestMarkdownRoundTrip(t *testing.T) {
+	board := sampleBoard()
+	var buf bytes.Buffer
+	if err := WriteMarkdown(&buf, board, Options{Completed: true}); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ReadMarkdown(&buf, "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clear := func(t *model.Todo) {
+		t.ID = ""
+		t.CreatedAt, t.UpdatedAt = time.Time{}, time.Time{}
+		t.Collapsed = false
+	}
+	assertProjects(t, strip(got, clear), strip(board, clear))
+}
+
+func TestReadMarkdownNotesAfterSubtask(t *testing.T) {
+	doc := strings.Join([]string{
+		"# Work",
+		"",
+		"- [ ] Parent",
+		"  - [ ] First",
+		"    about first",
+		"  more about parent",
+		"  - [x] Second",
+		"- [ ] Sibling",
+		"",
+		"Trailing paragraph.",
+		"  - [ ] Not nested under the paragraph",
+	}, "\n")
+	got, err := ReadMarkdown(strings.NewReader(doc), "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || len(got[0].Todos) != 3 {
+		t.Fatalf("got %+v", got)
+	}
+	parent := got[0].Todos[0]
+	if parent.Notes != "more about parent" {
+		t.Errorf("parent notes = %q", parent.Notes)
+	}
+	if len(parent.Subtasks) != 2 || parent.Subtasks[0].Notes != "about first" || !parent.Subtasks[1].Completed {
+		t.Errorf("subtasks = %+v", parent.Subtasks)
+	}
+	if got[0].Todos[1].Title != "Sibling" || got[0].Todos[2].Title != "Not nested under the paragraph" {
+		t.Errorf("top level = %q, %q", got[0].Todos[1].Title, got[0].Todos[2].Title)
+	}
+}
+
+func TestMarkdownMarkersInTitles(t *testing.T) {
+	board := []model.Project{{Name: "Work", Todos: []model.Todo{
+		{Title: "see 📅 2025-01-01 later"},
+		{Title: "⏫ urgent 🔼 or 🔽, ✅ 2025-01-02", Due: "2025-03-01", Priority: model.PriorityLow},
+		{Title: "Fix issue #login flow"},
+	}}}
+	var buf bytes.Buffer
+	if err := WriteMarkdown(&buf, board, Options{}); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ReadMarkdown(&buf, "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || len(got[0].Todos) != len(board[0].Todos) {
+		t.Fatalf("got %+v", got)
+	}
+	for i, want := range board[0].Todos {
+		todo := got[0].Todos[i]
+		if todo.Title != want.Title || todo.Due != want.Due || todo.Priority != want.Priority || todo.Completed || len(todo.Tags) != 0 {
+			t.Errorf("todo %d = %+v, want title %q, due %q, priority %q", i, todo, want.Title, want.Due, want.Priority)
+		}
+	}
+}
@@ -0,0 +1,372 @@
+package format
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/danjecu/focusboard-tui/internal/model"
+)
+
+const (
+	orgStamp  = "2006-01-02 Mon 15:04"
+	orgDay    = "2006-01-02 Mon"
+	orgFolded = "folded"
+	// orgEscape is the zero-width space org-mode suggests for keeping
+	// text from being read as markup.
+	orgEscape = "\u200b"
+)
+
+var orgPriority = map[model.Priority]string{
+	model.PriorityHigh:   "A",
+	model.PriorityMedium: "B",
+	model.PriorityLow:    "C",
+}
+
+// WriteOrg writes projects as an org-mode outline: a top-level headline
+// per project and a TODO or DONE headline per todo, one level deeper for
+// each level of subtasks. Priorities use [#A] to [#C], tags go at the end
+// of the headline, the due date becomes a DEADLINE, and the todo ID,
+// creation time and folded state are kept in a property drawer. Notes are
+// the body text, indented under the headline. Titles, project names and
+// note lines that would read back as org syntax get a zero-width space
+// that ReadOrg removes again.
+func WriteOrg(w io.Writer, projects []model.Project, opts Options) error {
+	bw := bufio.NewWriter(w)
+	for _, p := range projects {
+		fmt.Fprintf(bw, "* %s\n", escapeOrgTitle(p.Name))
+		writeOrgTodos(bw, p.Todos, p.Sort, 2, opts)
+	}
+	return bw.Flush()
+}
+
+func writeOrgTodos(w *bufio.Writer, todos []model.Todo, sort model.SortMode, level int, opts Options) {
+	indent := strings.Repeat(" ", level+1)
+	for _, i := range model.SortedIndices(todos, sort) {
+		t := todos[i]
+		if !opts.keep(t) {
+			continue
+		}
+		w.WriteString(orgHeadline(t, level) + "\n")
+
+		var planning []string
+		if t.Completed && !t.CompletedAt.IsZero() {
+			planning = append(planning, "CLOSED: ["+t.CompletedAt.Local().Format(orgStamp)+"]")
+		}
+		if due, ok := t.DueDate(); ok {
+			planning = append(planning, "DEADLINE: <"+due.Format(orgDay)+">")
+		}
+		if len(planning) > 0 {
+			w.WriteString(indent + strings.Join(planning, " ") + "\n")
+		}
+
+		w.WriteString(indent + ":PROPERTIES:\n")
+		fmt.Fprintf(w, "%s:ID: %s\n", indent, t.ID)
+		if !t.CreatedAt.IsZero() {
+			fmt.Fprintf(w, "%s:CREATED: [%s]\n", indent, t.CreatedAt.Local().Format(orgStamp))
+		}
+		if t.Collapsed {
+			fmt.Fprintf(w, "%s:VISIBILITY: %s\n", indent, orgFolded)
+		}
+		w.WriteString(indent + ":END:\n")
+
+		if t.Notes != "" {
+			for _, line := range strings.Split(t.Notes, "\n") {
+				if line == "" {
+					w.WriteString("\n")
+					continue
+				}
+				w.WriteString(indent + escapeOrgNote(line) + "\n")
+			}
+		}
+		writeOrgTodos(w, t.Subtasks, sort, level+1, opts)
+	}
+}
+
+func orgHeadline(t model.Todo, level int) string {
+	keyword := "TODO"
+	if t.Completed {
+		keyword = "DONE"
+	}
+	parts := []string{strings.Repeat("*", level), keyword}
+	if pri := orgPriority[t.Priority]; pri != "" {
+		parts = append(parts, "[#"+pri+"]")
+	}
+	title := escapeOrgTitle(t.Title)
+	if t.Link != "" {
+		title = "[[" + t.Link + "][" + title + "]]"
+	}
+	parts = append(parts, title)
+	if len(t.Tags) > 0 {
+		parts = append(parts, ":"+strings.Join(t.Tags, ":")+":")
+	}
+	return strings.Join(parts, " ")
+}
+
+// escapeOrgTitle protects a headline title from being read as a TODO
+// keyword or priority at its start, as tags at its end, or as a link, and
+// from ending a link description early. Every zero-width space it adds
+// follows a bracket or sits at either end, where unescapeOrgTitle drops
+// one; a title starting or ending with one gets another so that stays
+// unambiguous.
+func escapeOrgTitle(s string) string {
+	var b strings.Builder
+	keyword, _, _ := strings.Cut(s, " ")
+	if keyword == "TODO" || keyword == "DONE" || strings.HasPrefix(s, "[#") || strings.HasPrefix(s, orgEscape) {
+		b.WriteString(orgEscape)
+	}
+	for i, r := range s {
+		b.WriteRune(r)
+		if r != '[' && r != ']' {
+			continue
+		}
+		next := s[i+1:]
+		if strings.HasPrefix(next, "[") || strings.HasPrefix(next, "]") || strings.HasPrefix(next, orgEscape) {
+			b.WriteString(orgEscape)
+		}
+	}
+	if strings.HasSuffix(s, ":") || strings.HasSuffix(s, "]") || strings.HasSuffix(s, orgEscape) {
+		b.WriteString(orgEscape)
+	}
+	return b.String()
+}
+
+var orgBracketEscRe = regexp.MustCompile(`([\[\]])` + orgEscape)
+
+func unescapeOrgTitle(s string) string {
+	s = strings.TrimPrefix(s, orgEscape)
+	s = strings.TrimSuffix(s, orgEscape)
+	return orgBracketEscRe.ReplaceAllString(s, "$1")
+}
+
+// escapeOrgNote protects a note line that would read back as a planning
+// line or the start of a drawer.
+func escapeOrgNote(line string) string {
+	trimmed := strings.TrimSpace(line)
+	if orgPlanningRe.MatchString(trimmed) || orgDrawerRe.MatchString(trimmed) || strings.HasPrefix(line, orgEscape) {
+		return orgEscape + line
+	}
+	return line
+}
+
+var (
+	orgHeadlineRe = regexp.MustCompile(`^(\*+)(?:\s+(.*))?$`)
+	orgTagsRe     = regexp.MustCompile(`\s+:((?:[^\s:]+:)+)$`)
+	orgLinkRe     = regexp.MustCompile(`\[\[([^\]]+)\](?:\[(.*?)\])?\]`)
+	orgPlanningRe = regexp.MustCompile(`^(?:(?:CLOSED|DEADLINE|SCHEDULED):\s*[\[<][^\]>]*[\]>]\s*)+$`)
+	orgClosedRe   = regexp.MustCompile(`CLOSED:\s*\[(\d{4}-\d{2}-\d{2})[^\]\d]*(\d{1,2}:\d{2})?[^\]]*\]`)
+	orgDeadlineRe = regexp.MustCompile(`DEADLINE:\s*<(\d{4}-\d{2}-\d{2})`)
+	orgPropertyRe = regexp.MustCompile(`^:([^\s:]+):\s*(.*)$`)
+	orgDrawerRe   = regexp.MustCompile(`^:([^\s:]+):$`)
+	orgStampRe    = regexp.MustCompile(`^\[(\d{4}-\d{2}-\d{2})[^\]\d]*(\d{1,2}:\d{2})?[^\]]*\]$`)
+)
+
+type orgNode struct {
+	todo     model.Todo
+	level    int
+	notes    []string
+	children []*orgNode
+}
+
+// ReadOrg collects the TODO and DONE headlines of an org document.
+// Top-level headlines without a keyword start projects, and top-level
+// todos or todos before the first project go to defaultProject. Deeper
+// todos become subtasks of the todo headline above them, other headlines
+// are skipped, and body text becomes notes. The planning line and
+// property drawer written by WriteOrg are read back; other drawers are
+// ignored. Planning lines only count before the property drawer, as in
+// org-mode.
+func ReadOrg(r io.Reader, defaultProject string) ([]model.Project, error) {
+	now := time.Now()
+	type section struct {
+		name  string
+		roots []*orgNode
+	}
+	sections := []*section{{name: defaultProject}}
+	current := sections[0]
+	var stack []*orgNode
+	// body is the todo that plain lines belong to; nil after a headline
+	// that is not a todo. meta is true until its body text starts, and
+	// planned once the property drawer has closed.
+	var body *orgNode
+	meta, planned := false, false
+	drawer := ""
+
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	for sc.Scan() {
+		line := strings.ReplaceAll(strings.TrimRight(sc.Text(), " \t\r"), "\t", "    ")
+
+		if m := orgHeadlineRe.FindStringSubmatch(line); m != nil {
+			level := len(m[1])
+			for len(stack) > 0 && stack[len(stack)-1].level >= level {
+				stack = stack[:len(stack)-1]
+			}
+			body, meta, planned, drawer = nil, false, false, ""
+			t, ok := parseOrgHeadline(m[2], now)
+			if !ok {
+				if level == 1 {
+					current = &section{name: t.Title}
+					sections = append(sections, current)
+					stack = nil
+				}
+				continue
+			}
+			node := &orgNode{todo: t, level: level}
+			switch {
+			case len(stack) > 0:
+				top := stack[len(stack)-1]
+				top.children = append(top.children, node)
+			case level == 1:
+				sections[0].roots = append(sections[0].roots, node)
+			default:
+				current.roots = append(current.roots, node)
+			}
+			stack = append(stack, node)
+			body, meta = node, true
+			continue
+		}
+
+		if body == nil {
+			continue
+		}
+		trimmed := strings.TrimSpace(line)
+		if drawer != "" {
+			if strings.EqualFold(trimmed, ":END:") {
+				planned = planned || drawer == "PROPERTIES"
+				drawer = ""
+			} else if drawer == "PROPERTIES" {
+				if m := orgPropertyRe.FindStringSubmatch(trimmed); m != nil {
+					applyOrgProperty(&body.todo, strings.ToUpper(m[1]), m[2])
+				}
+			}
+			continue
+		}
+		if meta {
+			if m := orgDrawerRe.FindStringSubmatch(trimmed); m != nil && !strings.EqualFold(m[1], "END") {
+				drawer = strings.ToUpper(m[1])
+				continue
+			}
+			if !planned && orgPlanningRe.MatchString(trimmed) {
+				applyOrgPlanning(&body.todo, trimmed)
+				continue
+			}
+			if trimmed == "" {
+				continue
+			}
+			meta = false
+		}
+		if line == "" {
+			body.notes = append(body.notes, "")
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		body.notes = append(body.notes, strings.TrimPrefix(line[min(indent, body.level+1):], orgEscape))
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+
+	var projects []model.Project
+	for _, sec := range sections {
+		if len(sec.roots) == 0 {
+			continue
+		}
+		projects = append(projects, model.Project{ID: model.NewID(), Name: sec.name, Todos: orgTodos(sec.roots)})
+	}
+	return projects, nil
+}
+
+func orgTodos(nodes []*orgNode) []model.Todo {
+	todos := make([]model.Todo, len(nodes))
+	for i, n := range nodes {
+		t := n.todo
+		t.Notes = strings.Trim(strings.Join(n.notes, "\n"), "\n")
+		if len(n.children) > 0 {
+			t.Subtasks = orgTodos(n.children)
+		}
+		todos[i] = t
+	}
+	return todos
+}
+
+// parseOrgHeadline reads the text after the stars. It reports false for
+// headlines without a TODO or DONE keyword, returning just their title.
+func parseOrgHeadline(text string, now time.Time) (model.Todo, bool) {
+	var tags []string
+	if m := orgTagsRe.FindStringSubmatchIndex(text); m != nil {
+		tags = model.ParseTags(strings.ReplaceAll(text[m[2]:m[3]], ":", " "))
+		text = text[:m[0]]
+	}
+	keyword, rest, _ := strings.Cut(strings.TrimSpace(text), " ")
+	if keyword != "TODO" && keyword != "DONE" {
+		return model.Todo{Title: unescapeOrgTitle(strings.TrimSpace(text))}, false
+	}
+
+	t := model.NewTodo("", now)
+	t.Tags = tags
+	rest = strings.TrimSpace(rest)
+	if len(rest) >= 4 && strings.HasPrefix(rest, "[#") && rest[3] == ']' {
+		switch rest[2] {
+		case 'A':
+			t.Priority = model.PriorityHigh
+		case 'B':
+			t.Priority = model.PriorityMedium
+		default:
+			t.Priority = model.PriorityLow
+		}
+		rest = strings.TrimSpace(rest[4:])
+	}
+	if m := orgLinkRe.FindStringSubmatchIndex(rest); m != nil {
+		t.Link = rest[m[2]:m[3]]
+		desc := t.Link
+		if m[4] >= 0 {
+			desc = rest[m[4]:m[5]]
+		}
+		rest = rest[:m[0]] + desc + rest[m[1]:]
+	}
+	t.Title = unescapeOrgTitle(strings.TrimSpace(rest))
+	if keyword == "DONE" {
+		t.SetCompleted(true, now)
+	}
+	return t, true
+}
+
+func applyOrgPlanning(t *model.Todo, line string) {
+	if m := orgDeadlineRe.FindStringSubmatch(line); m != nil {
+		t.Due = m[1]
+	}
+	if m := orgClosedRe.FindStringSubmatch(line); m != nil && t.Completed {
+		if at, ok := orgTime(m[1], m[2]); ok {
+			t.CompletedAt = at
+		}
+	}
+}
+
+func applyOrgProperty(t *model.Todo, key, value string) {
+	switch key {
+	case "ID":
+		if value != "" {
+			t.ID = value
+		}
+	case "CREATED":
+		if m := orgStampRe.FindStringSubmatch(value); m != nil {
+			if at, ok := orgTime(m[1], m[2]); ok {
+				t.CreatedAt = at
+			}
+		}
+	case "VISIBILITY":
+		t.Collapsed = strings.EqualFold(value, orgFolded)
+	}
+}
+
+func orgTime(date, clock string) (time.Time, bool) {
+	if clock == "" {
+		clock = "00:00"
+	}
+	at, err := time.ParseInLocation(model.DateLayout+" 15:04", date+" "+clock, time.Local)
+	return at, err == nil
+}
@@ -0,0 +1,174 @@
+package format
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/danjecu/focusboard-tui/internal/model"
+)
+
+func TestOrgRoundTrip(t *testing.T) {
+	board := sampleBoard()
+	var buf bytes.Buffer
+	if err := WriteOrg(&buf, board, Options{Completed: true}); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ReadOrg(&buf, "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Links, notes, priorities, tags, nesting, DEADLINE, CLOSED, IDs,
+	// creation times and folding come back; the modification time is
+	// not written.
+	clear := func(t *model.Todo) { t.UpdatedAt = time.Time{} }
+	assertProjects(t, strip(got, clear), strip(board, clear))
+}
+
+// TestOrgRoundTripSyntax covers titles, project names and notes that
+// look like org syntax.
+func TestOrgRoundTripSyntax(t *testing.T) {
+	created := localDay(2025, 6, 1)
+	todo := func(id, title, notes string) model.Todo {
+		return model.Todo{ID: id, Title: title, Notes: notes, CreatedAt: created}
+	}
+	board := []model.Project{
+		{Name: "TODO list", Todos: []model.Todo{
+			todo("t1", "ends like a tag :x:", ""),
+			todo("t2", "[[odd]] title", ""),
+			todo("t3", "DEADLINE: <2025-01-01 Mon>", "DEADLINE: <2025-01-01 Mon>\nstill notes"),
+			todo("t4", "[#A] not a priority", ":PROPERTIES:\n:ID: other\n:END:"),
+			todo("t5", "TODO twice", "  :LOGBOOK:\n\u200balready escaped"),
+			todo("t6", "\u200b[\u200b]\u200b", "CLOSED: [2025-06-03 Tue 14:05]"),
+		}},
+		{Name: "DONE :old:", Todos: []model.Todo{
+			{ID: "t7", Title: "linked [[x]]", Link: "https://example.com/x", Tags: []string{"real"}, CreatedAt: created},
+			todo("t8", "a]] b", ""),
+		}},
+	}
+	var buf bytes.Buffer
+	if err := WriteOrg(&buf, board, Options{}); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ReadOrg(&buf, "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	clear := func(t *model.Todo) { t.UpdatedAt = time.Time{} }
+	assertProjects(t, strip(got, clear), strip(board, clear))
+}
+
+func TestWriteOrg(t *testing.T) {
+	board := []model.Project{{Name: "Work", Todos: []model.Todo{{
+		ID:          "t1",
+		Title:       "Review PR",
+		Link:        "https://example.com/pr/1",
+		Completed:   true,
+		CompletedAt: time.Date(2025, 6, 3, 14, 5, 0, 0, time.Local),
+		Due:         "2025-06-30",
+		Priority:    model.PriorityHigh,
+		Tags:        []string{"code", "team"},
+		Notes:       "first\n* not a headline",
+		Subtasks:    []model.Todo{{ID: "t2", Title: "Nested"}},
+	}}}}
+	var buf bytes.Buffer
+	if err := WriteOrg(&buf, board, Options{Completed: true}); err != nil {
+		t.Fatal(err)
+	}
+	want := strings.Join([]string{
+		"* Work",
+		"** DONE [#A] [[https://example.com/pr/1][Review PR]] :code:team:",
+		"   CLOSED: [2025-06-03 Tue 14:05] DEADLINE: <2025-06-30 Mon>",
+		"   :PROPERTIES:",
+		"   :ID: t1",
+		"   :END:",
+		"   first",
+		"   * not a headline",
+		"*** TODO Nested",
+		"    :PROPERTIES:",
+		"    :ID: t2",
+		"    :END:",
+		"",
+	}, "\n")
+	if buf.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestReadOrgHandWritten(t *testing.T) {
+	doc := strings.Join([]string{
+		"#+TITLE: Errands",
+		"* TODO Loose top-level todo",
+		"Body at column 0.",
+		"* Errands",
+		"Project intro text is ignored.",
+		"** Groceries",
+		"*** TODO [#B] Eggs :shop:",
+		"    SCHEDULED: <2025-06-20 Fri> DEADLINE: <2025-06-21 Sat>",
+		"    :LOGBOOK:",
+		"    - Note taken on [2025-06-19 Thu 10:00]",
+		"    :END:",
+		"    Free range.",
+		"**** DONE Check fridge",
+		"** DONE Post letter",
+		"CLOSED: [2025-06-10 Tue 14:05]",
+		"** TODO [[https://example.com/form]]",
+		"** Someday",
+	}, "\n")
+	got, err := ReadOrg(strings.NewReader(doc), "inbox")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].Name != "inbox" || got[1].Name != "Errands" {
+		t.Fatalf("projects = %+v", got)
+	}
+	if loose := got[0].Todos[0]; loose.Title != "Loose top-level todo" || loose.Notes != "Body at column 0." {
+		t.Errorf("loose todo = %+v", loose)
+	}
+
+	todos := got[1].Todos
+	if len(todos) != 3 {
+		t.Fatalf("got %d todos in Errands, want 3", len(todos))
+	}
+	eggs := todos[0]
+	if eggs.Title != "Eggs" || eggs.Priority != model.PriorityMedium || eggs.Due != "2025-06-21" ||
+		len(eggs.Tags) != 1 || eggs.Tags[0] != "shop" || eggs.Notes != "Free range." {
+		t.Errorf("eggs = %+v", eggs)
+	}
+	if len(eggs.Subtasks) != 1 || !eggs.Subtasks[0].Completed {
+		t.Errorf("eggs subtasks = %+v", eggs.Subtasks)
+	}
+	letter := todos[1]
+	if !letter.Completed || !letter.CompletedAt.Equal(time.Date(2025, 6, 10, 14, 5, 0, 0, time.Local)) {
+		t.Errorf("letter = %+v", letter)
+	}
+	if form := todos[2]; form.Title != "https://example.com/form" || form.Link != "https://example.com/form" {
+		t.Errorf("form = %+v", form)
+	}
+}
+
+func TestReadOrgPlanningAfterDrawer(t *testing.T) {
+	doc := strings.Join([]string{
+		"* Work",
+		"** TODO Report",
+		"   DEADLINE: <2025-06-30 Mon>",
+		"   :PROPERTIES:",
+		"   :ID: t1",
+		"   :END:",
+		"   :LOGBOOK:",
+		"   - State \"DONE\" from \"TODO\" [2025-06-19 Thu 10:00]",
+		"   :END:",
+		"   DEADLINE: <2025-01-01 Wed>",
+		"   is part of the notes",
+	}, "\n")
+	got, err := ReadOrg(strings.NewReader(doc), "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := got[0].Todos[0]
+	if report.ID != "t1" || report.Due != "2025-06-30" || report.Notes != "DEADLINE: <2025-01-01 Wed>\nis part of the notes" {
+		t.Errorf("report = %+v", report)
+	}
+}
@@ -0,0 +1,172 @@
+package format
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/danjecu/focusboard-tui/internal/model"
+)
+
+// row is one todo of a tabular export. Subtasks get their own row and
+// point to their parent by ID.
+type row struct {
+	project string
+	parent  string
+	todo    model.Todo
+}
+
+type column struct {
+	name  string
+	value func(r row) any
+}
+
+var columns = []column{
+	{"project", func(r row) any { return r.project }},
+	{"id", func(r row) any { return r.todo.ID }},
+	{"parent", func(r row) any { return r.parent }},
+	{"title", func(r row) any { return r.todo.Title }},
+	{"completed", func(r row) any { return r.todo.Completed }},
+	{"priority", func(r row) any { return string(r.todo.Priority) }},
+	{"due", func(r row) any { return r.todo.Due }},
+	{"tags", func(r row) any { return append([]string{}, r.todo.Tags...) }},
+	{"link", func(r row) any { return r.todo.Link }},
+	{"notes", func(r row) any { return r.todo.Notes }},
+	{"created", func(r row) any { return r.todo.CreatedAt }},
+	{"updated", func(r row) any { return r.todo.UpdatedAt }},
+	{"completed_at", func(r row) any { return r.todo.CompletedAt }},
+}
+
+func ColumnNames() []string {
+	names := make([]string, len(columns))
+	for i, c := range columns {
+		names[i] = c.name
+	}
+	return names
+}
+
+// CheckColumns reports the first name that is not a known column.
+func CheckColumns(names []string) error {
+	_, err := pickColumns(names)
+	return err
+}
+
+func pickColumns(names []string) ([]column, error) {
+	if len(names) == 0 {
+		return columns, nil
+	}
+	picked := make([]column, 0, len(names))
+	for _, name := range names {
+		found := false
+		for _, c := range columns {
+			if c.name == name {
+				picked = append(picked, c)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown column %q (choose from %s)", name, strings.Join(ColumnNames(), ", "))
+		}
+	}
+	return picked, nil
+}
+
+// rows flattens the projects depth first. Every todo is filtered on its
+// own, so a completed subtask of an open todo can be exported alone.
+func rows(projects []model.Project, opts Options) []row {
+	var out []row
+	var add func(project string, todos []model.Todo, parent string)
+	add = func(project string, todos []model.Todo, parent string) {
+		for _, t := range todos {
+			if opts.keep(t) {
+				out = append(out, row{project: project, parent: parent, todo: t})
+			}
+			add(project, t.Subtasks, t.ID)
+		}
+	}
+	for _, p := range projects {
+		add(p.Name, p.Todos, "")
+	}
+	return out
+}
+
+// WriteCSV writes a header and one row per todo. Tags are joined with
+// commas and timestamps use RFC 3339.
+func WriteCSV(w io.Writer, projects []model.Project, opts Options) error {
+	cols, err := pickColumns(opts.Columns)
+	if err != nil {
+		return err
+	}
+	cw := csv.NewWriter(w)
+	header := make([]string, len(cols))
+	for i, c := range cols {
+		header[i] = c.name
+	}
+	cw.Write(header)
+	for _, r := range rows(projects, opts) {
+		record := make([]string, len(cols))
+		for i, c := range cols {
+			record[i] = csvValue(c.value(r))
+		}
+		cw.Write(record)
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func csvValue(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case bool:
+		return fmt.Sprint(v)
+	case []string:
+		return strings.Join(v, ",")
+	case time.Time:
+		if v.IsZero() {
+			return ""
+		}
+		return v.Format(time.RFC3339)
+	}
+	return fmt.Sprint(v)
+}
+
+// WriteJSONL writes one JSON object per todo with the selected columns as
+// keys, in column order. Missing timestamps are null.
+func WriteJSONL(w io.Writer, projects []model.Project, opts Options) error {
+	cols, err := pickColumns(opts.Columns)
+	if err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(w)
+	for _, r := range rows(projects, opts) {
+		var obj bytes.Buffer
+		obj.WriteByte('{')
+		for i, c := range cols {
+			v := c.value(r)
+			if t, ok := v.(time.Time); ok && t.IsZero() {
+				v = nil
+			}
+			key, _ := json.Marshal(c.name)
+			val, err := json.Marshal(v)
+			if err != nil {
+				return err
+			}
+			if i > 0 {
+				obj.WriteByte(',')
+			}
+			obj.Write(key)
+			obj.WriteByte(':')
+			obj.Write(val)
+		}
+		obj.WriteString("}\n")
+		bw.Write(obj.Bytes())
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,121 @@
+package format
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/danjecu/focusboard-tui/internal/model"
+)
+
+func tabularBoard() []model.Project {
+	created := time.Date(2025, 6, 1, 9, 30, 0, 0, time.UTC)
+	return []model.Project{{Name: "Work, Home", Todos: []model.Todo{
+		{
+			ID:        "t1",
+			Title:     `Plan "Q3", roughly`,
+			Priority:  model.PriorityHigh,
+			Due:       "2025-07-01",
+			Tags:      []string{"plan", "team"},
+			Link:      "https://example.com/q3",
+			CreatedAt: created,
+			UpdatedAt: created,
+			Subtasks: []model.Todo{{
+				ID:          "t2",
+				Title:       "Draft",
+				Completed:   true,
+				CreatedAt:   created,
+				CompletedAt: time.Date(2025, 6, 2, 17, 0, 0, 0, time.UTC),
+			}},
+		},
+		{ID: "t3", Title: "Old", Completed: true},
+	}}}
+}
+
+func TestTabularExports(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		write func(io.Writer, []model.Project, Options) error
+		opts  Options
+		want  []string
+	}{
+		{
+			name:  "csv columns in the given order",
+			write: WriteCSV,
+			opts:  Options{Columns: []string{"title", "project", "tags", "due", "link"}},
+			want: []string{
+				"title,project,tags,due,link",
+				`"Plan ""Q3"", roughly","Work, Home","plan,team",2025-07-01,https://example.com/q3`,
+			},
+		},
+		{
+			name:  "csv with completed todos and subtask rows",
+			write: WriteCSV,
+			opts:  Options{Completed: true, Columns: []string{"id", "parent", "completed", "completed_at"}},
+			want: []string{
+				"id,parent,completed,completed_at",
+				"t1,,false,",
+				"t2,t1,true,2025-06-02T17:00:00Z",
+				"t3,,true,",
+			},
+		},
+		{
+			name:  "csv only completed keeps a subtask of an open todo",
+			write: WriteCSV,
+			opts:  Options{OnlyCompleted: true, Columns: []string{"title", "parent"}},
+			want:  []string{"title,parent", "Draft,t1", "Old,"},
+		},
+		{
+			name:  "csv all columns",
+			write: WriteCSV,
+			want: []string{
+				"project,id,parent,title,completed,priority,due,tags,link,notes,created,updated,completed_at",
+				`"Work, Home",t1,,"Plan ""Q3"", roughly",false,high,2025-07-01,"plan,team",https://example.com/q3,,2025-06-01T09:30:00Z,2025-06-01T09:30:00Z,`,
+			},
+		},
+		{
+			name:  "jsonl nulls for zero times and empty strings",
+			write: WriteJSONL,
+			opts:  Options{Completed: true, Columns: []string{"id", "due", "link", "tags", "created", "completed_at"}},
+			want: []string{
+				`{"id":"t1","due":"2025-07-01","link":"https://example.com/q3","tags":["plan","team"],"created":"2025-06-01T09:30:00Z","completed_at":null}`,
+				`{"id":"t2","due":"","link":"","tags":[],"created":"2025-06-01T09:30:00Z","completed_at":"2025-06-02T17:00:00Z"}`,
+				`{"id":"t3","due":"","link":"","tags":[],"created":null,"completed_at":null}`,
+			},
+		},
+		{
+			name:  "jsonl only completed",
+			write: WriteJSONL,
+			opts:  Options{OnlyCompleted: true, Columns: []string{"project", "title", "completed"}},
+			want: []string{
+				`{"project":"Work, Home","title":"Draft","completed":true}`,
+				`{"project":"Work, Home","title":"Old","completed":true}`,
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := tc.write(&buf, tabularBoard(), tc.opts); err != nil {
+				t.Fatal(err)
+			}
+			want := strings.Join(tc.want, "\n") + "\n"
+			if got := buf.String(); got != want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, want)
+			}
+		})
+	}
+}
+
+func TestTabularUnknownColumn(t *testing.T) {
+	for _, write := range []func(io.Writer, []model.Project, Options) error{WriteCSV, WriteJSONL} {
+		err := write(io.Discard, tabularBoard(), Options{Columns: []string{"title", "colour"}})
+		if err == nil || !strings.Contains(err.Error(), `"colour"`) {
+			t.Errorf("err = %v", err)
+		}
+	}
+	if err := CheckColumns([]string{"project", "completed_at"}); err != nil {
+		t.Errorf("CheckColumns: %v", err)
+	}
+}
@@ -0,0 +1,350 @@
+package format
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/danjecu/focusboard-tui/internal/model"
+)
+
+const twStamp = "20060102T150405Z"
+
+// twNamespace seeds the name-based UUIDs given to todos whose ID is not
+// already a UUID, so every export of a todo carries the same uuid.
+var twNamespace = [16]byte{0x6b, 0xa7, 0xb8, 0x14, 0x9d, 0xad, 0x11, 0xd1, 0x80, 0xb4, 0x00, 0xc0, 0x4f, 0xd4, 0x30, 0xc8}
+
+var twPriority = map[model.Priority]string{
+	model.PriorityHigh:   "H",
+	model.PriorityMedium: "M",
+	model.PriorityLow:    "L",
+}
+
+var (
+	twUUID = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	twURL  = regexp.MustCompile(`https?://\S+`)
+)
+
+type twTask struct {
+	UUID        string         `json:"uuid"`
+	Description string         `json:"description"`
+	Status      string         `json:"status"`
+	Project     string         `json:"project,omitempty"`
+	Priority    string         `json:"priority,omitempty"`
+	Tags        []string       `json:"tags,omitempty"`
+	Due         string         `json:"due,omitempty"`
+	Entry       string         `json:"entry,omitempty"`
+	Modified    string         `json:"modified,omitempty"`
+	End         string         `json:"end,omitempty"`
+	Annotations []twAnnotation `json:"annotations,omitempty"`
+	Depends     twDepends      `json:"depends,omitempty"`
+}
+
+type twAnnotation struct {
+	Entry       string `json:"entry,omitempty"`
+	Description string `json:"description"`
+}
+
+// twDepends reads both the comma separated string of Taskwarrior 2.5 and
+// the array of later versions.
+type twDepends []string
+
+func (d *twDepends) UnmarshalJSON(data []byte) error {
+	var list []string
+	if err := json.Unmarshal(data, &list); err == nil {
+		*d = list
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	*d = nil
+	for _, uuid := range strings.Split(s, ",") {
+		if uuid = strings.TrimSpace(uuid); uuid != "" {
+			*d = append(*d, uuid)
+		}
+	}
+	return nil
+}
+
+// WriteTaskwarrior writes a JSON array that `task import` accepts. Todos
+// keep their UUID if they were imported from Taskwarrior and otherwise get
+// one derived from their ID. The link and the notes become annotations,
+// and a todo depends on its subtasks.
+func WriteTaskwarrior(w io.Writer, projects []model.Project, opts Options) error {
+	var tasks []twTask
+	for _, p := range projects {
+		var add func(todos []model.Todo) []string
+		add = func(todos []model.Todo) []string {
+			var uuids []string
+			for _, t := range todos {
+				deps := add(t.Subtasks)
+				if !opts.keep(t) {
+					continue
+				}
+				task := twTaskOf(p.Name, t)
+				task.Depends = deps
+				tasks = append(tasks, task)
+				uuids = append(uuids, task.UUID)
+			}
+			return uuids
+		}
+		add(p.Todos)
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	buf.WriteString("[\n")
+	for i, task := range tasks {
+		if i > 0 {
+			buf.WriteString(",\n")
+		}
+		if err := enc.Encode(task); err != nil {
+			return err
+		}
+		buf.Truncate(buf.Len() - 1)
+	}
+	buf.WriteString("\n]\n")
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func twTaskOf(project string, t model.Todo) twTask {
+	task := twTask{
+		UUID:        twUUIDOf(t.ID),
+		Description: t.Title,
+		Status:      "pending",
+		Project:     project,
+		Priority:    twPriority[t.Priority],
+		Tags:        t.Tags,
+		Entry:       twTime(t.CreatedAt),
+		Modified:    twTime(t.UpdatedAt),
+	}
+	if t.Completed {
+		task.Status = "completed"
+		task.End = twTime(t.CompletedAt)
+		if task.End == "" {
+			task.End = task.Modified
+		}
+	}
+	if due, ok := t.DueDate(); ok {
+		task.Due = twTime(due)
+	}
+	for _, text := range []string{t.Link, t.Notes} {
+		if text != "" {
+			task.Annotations = append(task.Annotations, twAnnotation{Entry: task.Entry, Description: text})
+		}
+	}
+	return task
+}
+
+func twTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(twStamp)
+}
+
+// twUUIDOf returns id itself when it is a UUID and otherwise a version 5
+// UUID named by it.
+func twUUIDOf(id string) string {
+	if twUUID.MatchString(id) {
+		return strings.ToLower(id)
+	}
+	h := sha1.New()
+	h.Write(twNamespace[:])
+	h.Write([]byte(id))
+	u := h.Sum(nil)[:16]
+	u[6] = u[6]&0x0f | 0x50
+	u[8] = u[8]&0x3f | 0x80
+	s := hex.EncodeToString(u)
+	return s[0:8] + "-" + s[8:12] + "-" + s[12:16] + "-" + s[16:20] + "-" + s[20:]
+}
+
+// ReadTaskwarrior reads the output of `task export`, either a JSON array
+// or one object per line. The project attribute picks the project, tasks
+// without one go to defaultProject, and deleted tasks and recurrence
+// templates are skipped. The first URL in an annotation becomes the link
+// and other annotations become notes. A task that exactly one other task
+// of its project depends on becomes that task's subtask.
+func ReadTaskwarrior(r io.Reader, defaultProject string) ([]model.Project, error) {
+	tasks, err := decodeTaskwarrior(r)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+
+	type entry struct {
+		project  string
+		todo     model.Todo
+		children []*entry
+	}
+	byUUID := map[string]*entry{}
+	var entries []*entry
+	for _, task := range tasks {
+		if task.Status == "deleted" || task.Status == "recurring" {
+			continue
+		}
+		project := task.Project
+		if project == "" {
+			project = defaultProject
+		}
+		e := &entry{project: project, todo: twTodo(task, now)}
+		entries = append(entries, e)
+		if task.UUID != "" {
+			byUUID[task.UUID] = e
+		}
+	}
+
+	dependents := map[string]int{}
+	candidate := map[string]string{}
+	for _, task := range tasks {
+		if byUUID[task.UUID] == nil {
+			continue
+		}
+		for _, dep := range task.Depends {
+			dependents[dep]++
+			candidate[dep] = task.UUID
+		}
+	}
+	parent := map[string]string{}
+	for _, task := range tasks {
+		child := byUUID[task.UUID]
+		p, ok := candidate[task.UUID]
+		if child == nil || !ok || dependents[task.UUID] != 1 || byUUID[p].project != child.project {
+			continue
+		}
+		cycle := false
+		for x := p; x != ""; x = parent[x] {
+			if x == task.UUID {
+				cycle = true
+				break
+			}
+		}
+		if !cycle {
+			parent[task.UUID] = p
+			byUUID[p].children = append(byUUID[p].children, child)
+		}
+	}
+
+	var order []string
+	roots := map[string][]*entry{}
+	for _, e := range entries {
+		if _, ok := parent[e.todo.ID]; ok {
+			continue
+		}
+		if _, seen := roots[e.project]; !seen {
+			order = append(order, e.project)
+		}
+		roots[e.project] = append(roots[e.project], e)
+	}
+	var build func(entries []*entry) []model.Todo
+	build = func(entries []*entry) []model.Todo {
+		todos := make([]model.Todo, len(entries))
+		for i, e := range entries {
+			todos[i] = e.todo
+			if len(e.children) > 0 {
+				todos[i].Subtasks = build(e.children)
+			}
+		}
+		return todos
+	}
+	projects := make([]model.Project, len(order))
+	for i, name := range order {
+		projects[i] = model.Project{ID: model.NewID(), Name: name, Todos: build(roots[name])}
+	}
+	return projects, nil
+}
+
+func decodeTaskwarrior(r io.Reader) ([]twTask, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '[' {
+		var tasks []twTask
+		if err := json.Unmarshal(data, &tasks); err != nil {
+			return nil, fmt.Errorf("invalid taskwarrior JSON: %w", err)
+		}
+		return tasks, nil
+	}
+	var tasks []twTask
+	dec := json.NewDecoder(bytes.NewReader(data))
+	for dec.More() {
+		var task twTask
+		if err := dec.Decode(&task); err != nil {
+			return nil, fmt.Errorf("invalid taskwarrior JSON: %w", err)
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
+
+func twTodo(task twTask, now time.Time) model.Todo {
+	t := model.NewTodo(strings.TrimSpace(task.Description), now)
+	if task.UUID != "" {
+		t.ID = task.UUID
+	}
+	switch task.Priority {
+	case "H":
+		t.Priority = model.PriorityHigh
+	case "M":
+		t.Priority = model.PriorityMedium
+	case "L":
+		t.Priority = model.PriorityLow
+	}
+	t.Tags = model.ParseTags(strings.Join(task.Tags, " "))
+	if due, ok := parseTwTime(task.Due); ok {
+		t.Due = due.Local().Format(model.DateLayout)
+	}
+	if at, ok := parseTwTime(task.Entry); ok {
+		t.CreatedAt = at
+	}
+	if at, ok := parseTwTime(task.Modified); ok {
+		t.UpdatedAt = at
+	}
+
+	var notes []string
+	for _, a := range task.Annotations {
+		text := strings.TrimSpace(a.Description)
+		if t.Link == "" {
+			if url := twURL.FindString(text); url != "" {
+				t.Link = url
+				if url == text {
+					continue
+				}
+			}
+		}
+		if text != "" {
+			notes = append(notes, text)
+		}
+	}
+	t.Notes = strings.Join(notes, "\n")
+
+	if task.Status == "completed" {
+		t.Completed = true
+		t.CompletedAt = t.UpdatedAt
+		if at, ok := parseTwTime(task.End); ok {
+			t.CompletedAt = at
+		}
+	}
+	return t
+}
+
+func parseTwTime(s string) (time.Time, bool) {
+	for _, layout := range []string{twStamp, time.RFC3339} {
+		if at, err := time.Parse(layout, s); err == nil {
+			return at, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,149 @@
+package format
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/danjecu/focusboard-tui/internal/model"
+)
+
+func TestTwUUIDOf(t *testing.T) {
+	// Python: uuid.uuid5(uuid.NAMESPACE_X500, "a1b2c3d4e5f6")
+	if got, want := twUUIDOf("a1b2c3d4e5f6"), "4ae1370d-fe74-5884-9e70-917f037a4ff0"; got != want {
+		t.Errorf("twUUIDOf = %s, want %s", got, want)
+	}
+	if twUUIDOf("a1b2c3d4e5f6") != twUUIDOf("a1b2c3d4e5f6") || twUUIDOf("a") == twUUIDOf("b") {
+		t.Error("uuids are not stable per id")
+	}
+	if got := twUUIDOf("0F5A2C3E-1D2B-4C5D-8E9F-0A1B2C3D4E5F"); got != "0f5a2c3e-1d2b-4c5d-8e9f-0a1b2c3d4e5f" {
+		t.Errorf("existing uuid became %s", got)
+	}
+}
+
+// twExport lists tasks as `task export` prints them; depends uses both
+// the string form of Taskwarrior 2.5 and the array of later versions.
+var twExport = []string{
+	`{"uuid":"00000000-0000-4000-8000-000000000001","description":"Release","status":"pending","project":"Work","priority":"H","tags":["ship","team"],"due":"20250630T220000Z","entry":"20250601T093000Z","modified":"20250602T100000Z","depends":"00000000-0000-4000-8000-000000000002,00000000-0000-4000-8000-000000000003"}`,
+	`{"uuid":"00000000-0000-4000-8000-000000000002","description":"Changelog","status":"completed","project":"Work","entry":"20250601T093000Z","modified":"20250603T120000Z","end":"20250603T110000Z","annotations":[{"entry":"20250601T093000Z","description":"https://example.com/changelog"},{"entry":"20250601T093000Z","description":"draft in the wiki"}]}`,
+	`{"uuid":"00000000-0000-4000-8000-000000000003","description":"Tag","status":"waiting","project":"Work","depends":["00000000-0000-4000-8000-000000000004"],"annotations":[{"description":"see https://example.com/tag first"}]}`,
+	`{"uuid":"00000000-0000-4000-8000-000000000004","description":"Build","status":"pending","project":"Work"}`,
+	`{"uuid":"00000000-0000-4000-8000-000000000005","description":"Old","status":"deleted","project":"Work"}`,
+	`{"uuid":"00000000-0000-4000-8000-000000000006","description":"Weekly","status":"recurring","project":"Work"}`,
+	`{"uuid":"00000000-0000-4000-8000-000000000007","description":"Loose","status":"pending"}`,
+}
+
+func TestReadTaskwarrior(t *testing.T) {
+	inputs := map[string]string{
+		"array":      "[\n" + strings.Join(twExport, ",\n") + "\n]\n",
+		"json lines": strings.Join(twExport, "\n") + "\n",
+	}
+	for name, input := range inputs {
+		t.Run(name, func(t *testing.T) {
+			got, err := ReadTaskwarrior(strings.NewReader(input), "inbox")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != 2 || got[0].Name != "Work" || got[1].Name != "inbox" {
+				t.Fatalf("projects = %+v", got)
+			}
+			if len(got[1].Todos) != 1 || got[1].Todos[0].Title != "Loose" {
+				t.Errorf("inbox = %+v", got[1].Todos)
+			}
+
+			// Deleted tasks and recurrence templates are skipped and the
+			// rest nest by their dependencies.
+			work := got[0].Todos
+			if len(work) != 1 {
+				t.Fatalf("got %d top-level todos, want 1: %+v", len(work), work)
+			}
+			release := work[0]
+			if release.Title != "Release" || release.Priority != model.PriorityHigh || release.Completed ||
+				strings.Join(release.Tags, ",") != "ship,team" ||
+				!release.CreatedAt.Equal(time.Date(2025, 6, 1, 9, 30, 0, 0, time.UTC)) ||
+				!release.UpdatedAt.Equal(time.Date(2025, 6, 2, 10, 0, 0, 0, time.UTC)) {
+				t.Errorf("release = %+v", release)
+			}
+			if want := time.Date(2025, 6, 30, 22, 0, 0, 0, time.UTC).Local().Format(model.DateLayout); release.Due != want {
+				t.Errorf("due = %q, want %q", release.Due, want)
+			}
+			if len(release.Subtasks) != 2 {
+				t.Fatalf("release subtasks = %+v", release.Subtasks)
+			}
+			changelog, tag := release.Subtasks[0], release.Subtasks[1]
+			if !changelog.Completed || !changelog.CompletedAt.Equal(time.Date(2025, 6, 3, 11, 0, 0, 0, time.UTC)) ||
+				changelog.Link != "https://example.com/changelog" || changelog.Notes != "draft in the wiki" {
+				t.Errorf("changelog = %+v", changelog)
+			}
+			// A waiting task is still open; a URL inside an annotation
+			// becomes the link and the annotation stays a note.
+			if tag.Completed || tag.Link != "https://example.com/tag" || tag.Notes != "see https://example.com/tag first" {
+				t.Errorf("tag = %+v", tag)
+			}
+			if len(tag.Subtasks) != 1 || tag.Subtasks[0].Title != "Build" {
+				t.Errorf("tag subtasks = %+v", tag.Subtasks)
+			}
+		})
+	}
+}
+
+func TestReadTaskwarriorDependencyFallback(t *testing.T) {
+	input := strings.Join([]string{
+		// A and B depend on each other: one of them must stay top-level.
+		`{"uuid":"a","description":"A","status":"pending","project":"P","depends":["b"]}`,
+		`{"uuid":"b","description":"B","status":"pending","project":"P","depends":["a"]}`,
+		// C and D both depend on E, so E has no single parent.
+		`{"uuid":"c","description":"C","status":"pending","project":"P","depends":["e"]}`,
+		`{"uuid":"d","description":"D","status":"pending","project":"P","depends":["e"]}`,
+		`{"uuid":"e","description":"E","status":"pending","project":"P"}`,
+		// Dependencies across projects do not nest.
+		`{"uuid":"f","description":"F","status":"pending","project":"Q","depends":["c"]}`,
+	}, "\n")
+	got, err := ReadTaskwarrior(strings.NewReader(input), "inbox")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var titles []string
+	count := 0
+	for _, p := range got {
+		model.Walk(p.Todos, func(t *model.Todo, depth int) {
+			count++
+			if depth == 0 {
+				titles = append(titles, p.Name+"/"+t.Title)
+			}
+		})
+	}
+	if count != 6 {
+		t.Errorf("got %d todos, want 6", count)
+	}
+	if want := "P/B P/C P/D P/E Q/F"; strings.Join(titles, " ") != want {
+		t.Errorf("top level = %q, want %q", strings.Join(titles, " "), want)
+	}
+}
+
+func TestTaskwarriorRoundTrip(t *testing.T) {
+	board := sampleBoard()
+	var buf bytes.Buffer
+	if err := WriteTaskwarrior(&buf, board, Options{Completed: true}); err != nil {
+		t.Fatal(err)
+	}
+	var tasks []map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &tasks); err != nil {
+		t.Fatalf("output is not a JSON array: %v", err)
+	}
+	got, err := ReadTaskwarrior(&buf, "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// IDs become UUIDs and times come back in UTC; the folded state has
+	// no Taskwarrior attribute.
+	clear := func(t *model.Todo) {
+		t.ID = ""
+		t.Collapsed = false
+		t.CreatedAt, t.UpdatedAt, t.CompletedAt = t.CreatedAt.UTC(), t.UpdatedAt.UTC(), t.CompletedAt.UTC()
+	}
+	assertProjects(t, strip(got, clear), strip(board, clear))
+}
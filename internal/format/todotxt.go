@@ -0,0 +1,323 @@
+package format
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/danjecu/focusboard-tui/internal/model"
+)
+
+// Extension keys used for fields todo.txt has no syntax for. Only these
+// keys are read back; any other key:value text stays part of the title.
+var todoTxtKeys = []string{"due", "link", "id", "parent", "notes", "pri", "collapsed", "created", "updated", "done"}
+
+var todoTxtPriority = map[model.Priority]string{
+	model.PriorityHigh:   "A",
+	model.PriorityMedium: "B",
+	model.PriorityLow:    "C",
+}
+
+// WriteTodoTxt writes one todo.txt line per todo. The project becomes a
+// +Project tag with spaces turned into underscores, tags become @contexts,
+// priorities map to (A) high, (B) medium and (C) low, and completed todos
+// get the "x " prefix with their completion date. Subtasks are written
+// after their parent and point to it with parent:ID. Title words that
+// would read back as todo.txt syntax are percent-encoded, as are '%' and
+// '_' in project names. The todo.txt dates only hold the day, so the
+// creation, modification and completion times are also written in full
+// as created:, updated: and done: in RFC 3339.
+func WriteTodoTxt(w io.Writer, projects []model.Project, opts Options) error {
+	bw := bufio.NewWriter(w)
+	for _, p := range projects {
+		var write func(todos []model.Todo, parent string)
+		write = func(todos []model.Todo, parent string) {
+			for _, t := range todos {
+				if !opts.keep(t) {
+					continue
+				}
+				bw.WriteString(todoTxtLine(p.Name, t, parent) + "\n")
+				write(t.Subtasks, t.ID)
+			}
+		}
+		write(p.Todos, "")
+	}
+	return bw.Flush()
+}
+
+func todoTxtLine(project string, t model.Todo, parent string) string {
+	var parts []string
+	pri := todoTxtPriority[t.Priority]
+	if t.Completed {
+		parts = append(parts, "x")
+		if !t.CompletedAt.IsZero() {
+			parts = append(parts, day(t.CompletedAt))
+			if !t.CreatedAt.IsZero() {
+				parts = append(parts, day(t.CreatedAt))
+			}
+		}
+	} else {
+		if pri != "" {
+			parts = append(parts, "("+pri+")")
+		}
+		if !t.CreatedAt.IsZero() {
+			parts = append(parts, day(t.CreatedAt))
+		}
+	}
+
+	for i, word := range strings.Fields(t.Title) {
+		parts = append(parts, escapeTodoTxtWord(word, i == 0))
+	}
+	if project != "" {
+		parts = append(parts, "+"+todoTxtProject.Replace(project))
+	}
+	for _, tag := range t.Tags {
+		parts = append(parts, "@"+tag)
+	}
+	ext := func(key, value string) {
+		if value != "" {
+			parts = append(parts, key+":"+value)
+		}
+	}
+	ext("due", t.Due)
+	ext("link", t.Link)
+	ext("id", t.ID)
+	ext("parent", parent)
+	ext("notes", url.PathEscape(t.Notes))
+	if t.Completed {
+		ext("pri", pri)
+	}
+	if t.Collapsed {
+		ext("collapsed", "1")
+	}
+	ext("created", stamp(t.CreatedAt))
+	ext("updated", stamp(t.UpdatedAt))
+	ext("done", stamp(t.CompletedAt))
+	return strings.Join(parts, " ")
+}
+
+var todoTxtProject = strings.NewReplacer("%", "%25", "_", "%5F", " ", "_")
+
+// escapeTodoTxtWord percent-encodes the first character of a title word
+// that would otherwise be read as a +project, @context or key:value
+// extension, or, as the first word, as a completion mark, priority or
+// date. '%' itself is escaped so that unescapeTodoTxt is unambiguous.
+func escapeTodoTxtWord(word string, first bool) string {
+	word = strings.ReplaceAll(word, "%", "%25")
+	if len(word) > 1 && (word[0] == '+' || word[0] == '@') ||
+		first && (word == "x" || isTodoTxtPriority(word) || isDate(word)) {
+		return fmt.Sprintf("%%%02X", word[0]) + word[1:]
+	}
+	if key, value, ok := strings.Cut(word, ":"); ok && value != "" && containsKey(key) {
+		return key + "%3A" + value
+	}
+	return word
+}
+
+func unescapeTodoTxt(s string) string {
+	if u, err := url.PathUnescape(s); err == nil {
+		return u
+	}
+	return s
+}
+
+func isTodoTxtPriority(f string) bool {
+	return len(f) == 3 && f[0] == '(' && f[2] == ')' && f[1] >= 'A' && f[1] <= 'Z'
+}
+
+func day(t time.Time) string {
+	return t.Local().Format(model.DateLayout)
+}
+
+func stamp(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339Nano)
+}
+
+// ReadTodoTxt parses todo.txt lines as written by WriteTodoTxt or any
+// other todo.txt tool. The first +Project of a line picks its project,
+// lines without one go to defaultProject, and a parent:ID naming an
+// earlier line makes the todo its subtask.
+func ReadTodoTxt(r io.Reader, defaultProject string) ([]model.Project, error) {
+	now := time.Now()
+	type entry struct {
+		project  string
+		todo     model.Todo
+		parent   string
+		children []*entry
+	}
+	byID := map[string]*entry{}
+	var order []string
+	roots := map[string][]*entry{}
+
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" {
+			continue
+		}
+		project, t, parent := parseTodoTxtLine(line, now)
+		if project == "" {
+			project = defaultProject
+		}
+		e := &entry{project: project, todo: t, parent: parent}
+		if p, ok := byID[parent]; ok && parent != "" {
+			p.children = append(p.children, e)
+		} else {
+			if _, seen := roots[project]; !seen {
+				order = append(order, project)
+			}
+			roots[project] = append(roots[project], e)
+		}
+		if t.ID != "" {
+			byID[t.ID] = e
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+
+	var build func(entries []*entry) []model.Todo
+	build = func(entries []*entry) []model.Todo {
+		todos := make([]model.Todo, len(entries))
+		for i, e := range entries {
+			todos[i] = e.todo
+			if len(e.children) > 0 {
+				todos[i].Subtasks = build(e.children)
+			}
+		}
+		return todos
+	}
+	projects := make([]model.Project, len(order))
+	for i, name := range order {
+		projects[i] = model.Project{ID: model.NewID(), Name: name, Todos: build(roots[name])}
+	}
+	return projects, nil
+}
+
+func parseTodoTxtLine(line string, now time.Time) (project string, t model.Todo, parent string) {
+	fields := strings.Fields(line)
+	i := 0
+	var completedAt, createdAt, updatedAt time.Time
+	var pri string
+	date := func() (time.Time, bool) {
+		if i >= len(fields) {
+			return time.Time{}, false
+		}
+		d, err := time.ParseInLocation(model.DateLayout, fields[i], time.Local)
+		if err != nil {
+			return time.Time{}, false
+		}
+		i++
+		return d, true
+	}
+
+	done := fields[0] == "x"
+	if done {
+		i++
+		if d, ok := date(); ok {
+			completedAt = d
+			createdAt, _ = date()
+		}
+	} else {
+		if f := fields[0]; isTodoTxtPriority(f) {
+			pri = f[1:2]
+			i++
+		}
+		createdAt, _ = date()
+	}
+
+	var words []string
+	for _, f := range fields[i:] {
+		switch {
+		case len(f) > 1 && f[0] == '+':
+			if project == "" {
+				project = unescapeTodoTxt(strings.ReplaceAll(f[1:], "_", " "))
+			}
+		case len(f) > 1 && f[0] == '@':
+			t.Tags = append(t.Tags, model.ParseTags(f[1:])...)
+		default:
+			key, value, ok := strings.Cut(f, ":")
+			if !ok || value == "" || !containsKey(key) {
+				words = append(words, unescapeTodoTxt(f))
+				continue
+			}
+			switch key {
+			case "due":
+				t.Due = value
+			case "link":
+				t.Link = value
+			case "id":
+				t.ID = value
+			case "parent":
+				parent = value
+			case "notes":
+				if notes, err := url.PathUnescape(value); err == nil {
+					t.Notes = notes
+				}
+			case "pri":
+				pri = value
+			case "collapsed":
+				t.Collapsed = value == "1" || value == "true"
+			case "created", "updated", "done":
+				ts, err := time.Parse(time.RFC3339Nano, value)
+				if err != nil {
+					break
+				}
+				switch key {
+				case "created":
+					createdAt = ts
+				case "updated":
+					updatedAt = ts
+				default:
+					completedAt = ts
+				}
+			}
+		}
+	}
+
+	t.Title = strings.Join(words, " ")
+	if t.ID == "" {
+		t.ID = model.NewID()
+	}
+	switch pri {
+	case "A":
+		t.Priority = model.PriorityHigh
+	case "B":
+		t.Priority = model.PriorityMedium
+	case "":
+	default:
+		t.Priority = model.PriorityLow
+	}
+	if createdAt.IsZero() {
+		createdAt = now
+	}
+	if updatedAt.IsZero() {
+		updatedAt = now
+	}
+	t.CreatedAt = createdAt
+	t.UpdatedAt = updatedAt
+	if done {
+		if completedAt.IsZero() {
+			completedAt = now
+		}
+		t.Completed = true
+		t.CompletedAt = completedAt
+	}
+	return project, t, parent
+}
+
+func containsKey(key string) bool {
+	for _, k := range todoTxtKeys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,94 @@
+package format
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/danjecu/focusboard-tui/internal/model"
+)
+
+func TestTodoTxtRoundTrip(t *testing.T) {
+	board := sampleBoard()
+	// Times off midnight, in another zone and below the second, which the
+	// todo.txt dates alone cannot hold.
+	off := time.FixedZone("", 5*3600+30*60)
+	for i := range board {
+		model.Walk(board[i].Todos, func(t *model.Todo, depth int) {
+			t.CreatedAt = time.Date(2025, 6, 1, 9, 30, 15, 123456789, time.Local)
+			t.UpdatedAt = time.Date(2025, 6, 2, 23, 59, 59, 0, off)
+			if t.Completed {
+				t.CompletedAt = time.Date(2025, 6, 3, 0, 15, depth, 0, time.UTC)
+			}
+		})
+	}
+	var buf bytes.Buffer
+	if err := WriteTodoTxt(&buf, board, Options{Completed: true}); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ReadTodoTxt(&buf, "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Every todo field comes back; only project IDs and sort orders have
+	// no place in todo.txt.
+	keep := func(*model.Todo) {}
+	assertProjects(t, strip(got, keep), strip(board, keep))
+}
+
+func TestTodoTxtEscapesSyntaxInTitles(t *testing.T) {
+	board := []model.Project{{Name: "My_Proj Two", Todos: []model.Todo{
+		{ID: "t1", Title: "Email @bob about +release plan"},
+		{ID: "t2", Title: "x (A) 2025-01-01 due:soon 100% done"},
+	}}}
+	var buf bytes.Buffer
+	if err := WriteTodoTxt(&buf, board, Options{}); err != nil {
+		t.Fatal(err)
+	}
+	text := buf.String()
+	for _, syntax := range []string{" @bob", " +release", " due:soon"} {
+		if strings.Contains(text, syntax) {
+			t.Errorf("%q written unescaped:\n%s", syntax, text)
+		}
+	}
+
+	got, err := ReadTodoTxt(strings.NewReader(text), "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].Name != "My_Proj Two" {
+		t.Fatalf("projects = %+v", got)
+	}
+	for i, want := range board[0].Todos {
+		todo := got[0].Todos[i]
+		if todo.Title != want.Title || todo.Completed || todo.Priority != "" || todo.Due != "" || len(todo.Tags) != 0 {
+			t.Errorf("todo %d = %+v, want title %q and nothing else", i, todo, want.Title)
+		}
+	}
+}
+
+func TestReadTodoTxtFromOtherTools(t *testing.T) {
+	text := "(A) 2025-06-01 Call mom +Family @phone due:2025-06-10\n" +
+		"x 2025-06-03 2025-06-01 Pay rent +Home_Admin\n" +
+		"Water plants 50% more\n"
+	got, err := ReadTodoTxt(strings.NewReader(text), "Inbox")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("got %d projects", len(got))
+	}
+	call := got[0].Todos[0]
+	if got[0].Name != "Family" || call.Title != "Call mom" || call.Priority != model.PriorityHigh || call.Due != "2025-06-10" || call.Tags[0] != "phone" {
+		t.Errorf("first = %q %+v", got[0].Name, call)
+	}
+	rent := got[1].Todos[0]
+	if got[1].Name != "Home Admin" || !rent.Completed || !rent.CompletedAt.Equal(localDay(2025, 6, 3)) {
+		t.Errorf("second = %q %+v", got[1].Name, rent)
+	}
+	if got[2].Name != "Inbox" || got[2].Todos[0].Title != "Water plants 50% more" {
+		t.Errorf("third = %q %q", got[2].Name, got[2].Todos[0].Title)
+	}
+}
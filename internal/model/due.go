@@ -0,0 +1,104 @@
+package model
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DateLayout is the format due dates are stored in.
+const DateLayout = "2006-01-02"
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "sunday": time.Sunday,
+	"mon": time.Monday, "monday": time.Monday,
+	"tue": time.Tuesday, "tues": time.Tuesday, "tuesday": time.Tuesday,
+	"wed": time.Wednesday, "wednesday": time.Wednesday,
+	"thu": time.Thursday, "thurs": time.Thursday, "thursday": time.Thursday,
+	"fri": time.Friday, "friday": time.Friday,
+	"sat": time.Saturday, "saturday": time.Saturday,
+}
+
+// DueDate returns the todo's due date at local midnight.
+func (t Todo) DueDate() (time.Time, bool) {
+	if t.Due == "" {
+		return time.Time{}, false
+	}
+	d, err := time.ParseInLocation(DateLayout, t.Due, time.Local)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return d, true
+}
+
+// DaysUntilDue returns how many calendar days separate now from the due
+// date. Negative values mean the todo is overdue.
+func (t Todo) DaysUntilDue(now time.Time) (int, bool) {
+	d, ok := t.DueDate()
+	if !ok {
+		return 0, false
+	}
+	today := midnight(now)
+	return int(math.Round(d.Sub(today).Hours() / 24)), true
+}
+
+// ParseDue turns user input into a stored due date. It accepts
+// YYYY-MM-DD, MM-DD, today, tomorrow, +Nd, +Nw and weekday names.
+// Empty input clears the date.
+func ParseDue(input string, now time.Time) (string, error) {
+	s := strings.ToLower(strings.TrimSpace(input))
+	today := midnight(now)
+
+	switch s {
+	case "", "none", "-":
+		return "", nil
+	case "today", "tod":
+		return today.Format(DateLayout), nil
+	case "tomorrow", "tmr", "tom":
+		return today.AddDate(0, 0, 1).Format(DateLayout), nil
+	}
+
+	if wd, ok := weekdays[s]; ok {
+		days := int(wd-today.Weekday()+7) % 7
+		if days == 0 {
+			days = 7
+		}
+		return today.AddDate(0, 0, days).Format(DateLayout), nil
+	}
+
+	if strings.HasPrefix(s, "+") {
+		unit := byte('d')
+		num := s[1:]
+		if n := len(num); n > 0 && (num[n-1] == 'd' || num[n-1] == 'w') {
+			unit = num[n-1]
+			num = num[:n-1]
+		}
+		n, err := strconv.Atoi(num)
+		if err != nil || n < 0 {
+			return "", fmt.Errorf("invalid relative date %q", input)
+		}
+		if unit == 'w' {
+			n *= 7
+		}
+		return today.AddDate(0, 0, n).Format(DateLayout), nil
+	}
+
+	if d, err := time.ParseInLocation(DateLayout, s, time.Local); err == nil {
+		return d.Format(DateLayout), nil
+	}
+	if d, err := time.ParseInLocation("01-02", s, time.Local); err == nil {
+		d = time.Date(today.Year(), d.Month(), d.Day(), 0, 0, 0, 0, time.Local)
+		if d.Before(today) {
+			d = d.AddDate(1, 0, 0)
+		}
+		return d.Format(DateLayout), nil
+	}
+	return "", fmt.Errorf("unrecognised date %q", input)
+}
+
+func midnight(t time.Time) time.Time {
+	y, mo, d := t.Date()
+	return time.Date(y, mo, d, 0, 0, 0, 0, time.Local)
+}
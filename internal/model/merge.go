@@ -0,0 +1,86 @@
+package model
+
+import "strings"
+
+// MergedProject describes what Store.Merge did to one project.
+type MergedProject struct {
+	Name    string
+	Created bool
+	Added   []Todo
+	Skipped []string
+}
+
+// Merge adds the todos of projects to the store. Projects are matched by
+// name, ignoring case, and created when missing. A top-level todo whose
+// title was already in the target project before the merge is skipped so
+// importing the same file twice does not duplicate it; repeated titles
+// within the import are all added. Todos whose ID is already taken or
+// missing get a new one.
+func (s *Store) Merge(projects []Project) []MergedProject {
+	var result []MergedProject
+	// existing holds the titles each project had before the merge, keyed
+	// by project index, so todos added here do not count.
+	existing := map[int]map[string]bool{}
+	for _, src := range projects {
+		pi := -1
+		for i, p := range s.Projects {
+			if strings.EqualFold(p.Name, src.Name) {
+				pi = i
+				break
+			}
+		}
+		created := pi < 0
+		if created {
+			s.Projects = append(s.Projects, Project{ID: NewID(), Name: src.Name, Sort: src.Sort, Todos: []Todo{}})
+			pi = len(s.Projects) - 1
+		}
+		if existing[pi] == nil {
+			existing[pi] = titleSet(s.Projects[pi].Todos)
+		}
+
+		mp := MergedProject{Name: s.Projects[pi].Name, Created: created}
+		for _, t := range src.Todos {
+			if existing[pi][strings.ToLower(t.Title)] {
+				mp.Skipped = append(mp.Skipped, t.Title)
+				continue
+			}
+			t = t.Clone()
+			s.freshIDs(&t)
+			s.Projects[pi].Todos = append(s.Projects[pi].Todos, t)
+			mp.Added = append(mp.Added, t)
+		}
+		result = appendMerged(result, mp)
+	}
+	return result
+}
+
+func (s *Store) freshIDs(t *Todo) {
+	fix := func(t *Todo) {
+		if _, taken := s.FindTodo(t.ID); taken != nil || t.ID == "" {
+			t.ID = NewID()
+		}
+	}
+	fix(t)
+	Walk(t.Subtasks, func(sub *Todo, _ int) { fix(sub) })
+}
+
+func titleSet(todos []Todo) map[string]bool {
+	set := make(map[string]bool, len(todos))
+	for _, t := range todos {
+		set[strings.ToLower(t.Title)] = true
+	}
+	return set
+}
+
+// appendMerged folds mp into an earlier entry for the same project, which
+// keeps that entry's Created flag.
+func appendMerged(result []MergedProject, mp MergedProject) []MergedProject {
+	for i := range result {
+		if result[i].Name == mp.Name {
+			result[i].Added = append(result[i].Added, mp.Added...)
+			result[i].Skipped = append(result[i].Skipped, mp.Skipped...)
+			return result
+		}
+	}
+	return append(result, mp)
+}
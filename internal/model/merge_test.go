@@ -0,0 +1,48 @@
+package model
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestMergeSkipsOnlyExistingTitles(t *testing.T) {
+	s := Store{Projects: []Project{{ID: "p1", Name: "Work", Todos: []Todo{{ID: "t1", Title: "Write report"}}}}}
+	got := s.Merge([]Project{
+		{Name: "work", Todos: []Todo{
+			{Title: "Call Bob"},
+			{Title: "Call Bob"},
+			{Title: "write REPORT"},
+		}},
+		// A second source project for the same target sees the same
+		// titles as the first, not the ones just added.
+		{Name: "Work", Todos: []Todo{{Title: "Call Bob"}, {ID: "t1", Title: "Review"}}},
+		{Name: "Home", Todos: []Todo{{Title: "Buy milk"}, {Title: "Buy milk"}}},
+	})
+
+	if len(got) != 2 {
+		t.Fatalf("got %d merged projects, want 2: %+v", len(got), got)
+	}
+	work, home := got[0], got[1]
+	if work.Name != "Work" || work.Created || len(work.Added) != 4 || !slices.Equal(work.Skipped, []string{"write REPORT"}) {
+		t.Errorf("work = %+v", work)
+	}
+	if home.Name != "Home" || !home.Created || len(home.Added) != 2 || len(home.Skipped) != 0 {
+		t.Errorf("home = %+v", home)
+	}
+
+	var titles []string
+	ids := map[string]bool{}
+	for _, todo := range s.Projects[0].Todos {
+		titles = append(titles, todo.Title)
+		if todo.ID == "" || ids[todo.ID] {
+			t.Errorf("missing or duplicate id %q", todo.ID)
+		}
+		ids[todo.ID] = true
+	}
+	if want := []string{"Write report", "Call Bob", "Call Bob", "Call Bob", "Review"}; !slices.Equal(titles, want) {
+		t.Errorf("titles = %q, want %q", titles, want)
+	}
+	if len(s.Projects) != 2 || len(s.Projects[1].Todos) != 2 {
+		t.Errorf("projects = %+v", s.Projects)
+	}
+}
@@ -0,0 +1,53 @@
+package model
+
+import "fmt"
+
+type Priority string
+
+const (
+	PriorityNone   Priority = ""
+	PriorityLow    Priority = "low"
+	PriorityMedium Priority = "medium"
+	PriorityHigh   Priority = "high"
+)
+
+var priorities = []Priority{PriorityNone, PriorityLow, PriorityMedium, PriorityHigh}
+
+// Rank orders priorities from none (0) to high (3).
+func (p Priority) Rank() int {
+	for i, q := range priorities {
+		if q == p {
+			return i
+		}
+	}
+	return 0
+}
+
+func (p Priority) Raise() Priority {
+	return priorities[(p.Rank()+1)%len(priorities)]
+}
+
+func (p Priority) Lower() Priority {
+	return priorities[(p.Rank()+len(priorities)-1)%len(priorities)]
+}
+
+func (p Priority) String() string {
+	if p == PriorityNone {
+		return "none"
+	}
+	return string(p)
+}
+
+func ParsePriority(s string) (Priority, error) {
+	switch s {
+	case "", "none":
+		return PriorityNone, nil
+	case "low", "l":
+		return PriorityLow, nil
+	case "medium", "med", "m":
+		return PriorityMedium, nil
+	case "high", "h":
+		return PriorityHigh, nil
+	}
+	return PriorityNone, fmt.Errorf("unknown priority %q", s)
+}
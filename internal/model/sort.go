@@ -0,0 +1,74 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+)
+
+type SortMode string
+
+const (
+	SortManual   SortMode = ""
+	SortPriority SortMode = "priority"
+	SortDue      SortMode = "due"
+	SortCreated  SortMode = "created"
+)
+
+var sortModes = []SortMode{SortManual, SortPriority, SortDue, SortCreated}
+
+func (s SortMode) Next() SortMode {
+	for i, m := range sortModes {
+		if m == s {
+			return sortModes[(i+1)%len(sortModes)]
+		}
+	}
+	return SortManual
+}
+
+func (s SortMode) String() string {
+	if s == SortManual {
+		return "manual"
+	}
+	return string(s)
+}
+
+func ParseSortMode(s string) (SortMode, error) {
+	switch s {
+	case "", "manual":
+		return SortManual, nil
+	case "priority", "due", "created":
+		return SortMode(s), nil
+	}
+	return SortManual, fmt.Errorf("unknown sort order %q", s)
+}
+
+// SortedIndices returns the positions of todos in display order for the
+// given mode. The slice itself is left untouched so manual order survives.
+func SortedIndices(todos []Todo, mode SortMode) []int {
+	idx := make([]int, len(todos))
+	for i := range idx {
+		idx[i] = i
+	}
+
+	var less func(a, b Todo) bool
+	switch mode {
+	case SortPriority:
+		less = func(a, b Todo) bool { return a.Priority.Rank() > b.Priority.Rank() }
+	case SortDue:
+		less = func(a, b Todo) bool {
+			if a.Due == "" || b.Due == "" {
+				return a.Due != "" && b.Due == ""
+			}
+			return a.Due < b.Due
+		}
+	case SortCreated:
+		less = func(a, b Todo) bool { return a.CreatedAt.After(b.CreatedAt) }
+	default:
+		return idx
+	}
+
+	sort.SliceStable(idx, func(i, j int) bool {
+		return less(todos[idx[i]], todos[idx[j]])
+	})
+	return idx
+}
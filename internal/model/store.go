@@ -0,0 +1,121 @@
+package model
+
+// Clone returns a deep copy of the store, so mutations on one side never
+// show up on the other.
+func (s Store) Clone() Store {
+	c := s
+	c.Projects = make([]Project, len(s.Projects))
+	for i, p := range s.Projects {
+		c.Projects[i] = p.Clone()
+	}
+	return c
+}
+
+func (p Project) Clone() Project {
+	c := p
+	c.Todos = make([]Todo, len(p.Todos))
+	for i, t := range p.Todos {
+		c.Todos[i] = t.Clone()
+	}
+	return c
+}
+
+func (t Todo) Clone() Todo {
+	c := t
+	if t.Tags != nil {
+		c.Tags = append([]string(nil), t.Tags...)
+	}
+	if t.Subtasks != nil {
+		c.Subtasks = make([]Todo, len(t.Subtasks))
+		for i, sub := range t.Subtasks {
+			c.Subtasks[i] = sub.Clone()
+		}
+	}
+	return c
+}
+
+// EnsureIDs assigns IDs to projects and todos that have none and reports
+// whether it changed anything.
+func (s *Store) EnsureIDs() bool {
+	changed := false
+	for i := range s.Projects {
+		p := &s.Projects[i]
+		if p.ID == "" {
+			p.ID = NewID()
+			changed = true
+		}
+		Walk(p.Todos, func(t *Todo, _ int) {
+			if t.ID == "" {
+				t.ID = NewID()
+				changed = true
+			}
+		})
+	}
+	return changed
+}
+
+func (s *Store) ProjectIndex(id string) int {
+	for i, p := range s.Projects {
+		if p.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *Store) Project(id string) *Project {
+	if i := s.ProjectIndex(id); i >= 0 {
+		return &s.Projects[i]
+	}
+	return nil
+}
+
+// FindTodo returns the todo with the given ID, at any depth, and the
+// project holding it.
+func (s *Store) FindTodo(id string) (*Project, *Todo) {
+	for i := range s.Projects {
+		p := &s.Projects[i]
+		if t := p.Todo(id); t != nil {
+			return p, t
+		}
+	}
+	return nil, nil
+}
+
+func (s *Store) RemoveProject(id string) (Project, bool) {
+	i := s.ProjectIndex(id)
+	if i < 0 {
+		return Project{}, false
+	}
+	p := s.Projects[i]
+	s.Projects = append(s.Projects[:i], s.Projects[i+1:]...)
+	return p, true
+}
+
+func (s *Store) RemoveTodo(id string) (Todo, bool) {
+	p, _ := s.FindTodo(id)
+	if p == nil {
+		return Todo{}, false
+	}
+	return p.RemoveTodo(id)
+}
+
+func (p *Project) Todo(id string) *Todo {
+	list, i, _ := p.Locate(id)
+	if list == nil {
+		return nil
+	}
+	return &(*list)[i]
+}
+
+// RemoveTodo removes the todo, together with its subtasks, wherever it sits
+// in the project.
+func (p *Project) RemoveTodo(id string) (Todo, bool) {
+	list, i, _ := p.Locate(id)
+	if list == nil {
+		return Todo{}, false
+	}
+	t := (*list)[i]
+	*list = append((*list)[:i], (*list)[i+1:]...)
+	return t, true
+}
@@ -0,0 +1,52 @@
+package model
+
+import (
+	"sort"
+	"strings"
+)
+
+// ParseTags splits comma or space separated input into lower-case tags,
+// dropping a leading '#' and duplicates.
+func ParseTags(input string) []string {
+	fields := strings.FieldsFunc(input, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t' || r == '\n'
+	})
+	var tags []string
+	seen := map[string]bool{}
+	for _, f := range fields {
+		tag := strings.ToLower(strings.TrimLeft(f, "#"))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		tags = append(tags, tag)
+	}
+	return tags
+}
+
+func (t Todo) HasTag(tag string) bool {
+	for _, tt := range t.Tags {
+		if tt == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// Tags returns every tag used in the store, sorted.
+func (s Store) Tags() []string {
+	seen := map[string]bool{}
+	var tags []string
+	for _, p := range s.Projects {
+		Walk(p.Todos, func(t *Todo, _ int) {
+			for _, tag := range t.Tags {
+				if !seen[tag] {
+					seen[tag] = true
+					tags = append(tags, tag)
+				}
+			}
+		})
+	}
+	sort.Strings(tags)
+	return tags
+}
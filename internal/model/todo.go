@@ -0,0 +1,30 @@
+package model
+
+import "time"
+
+func NewTodo(title string, now time.Time) Todo {
+	return Todo{ID: NewID(), Title: title, CreatedAt: now, UpdatedAt: now}
+}
+
+// Touch records that the todo was modified.
+func (t *Todo) Touch(now time.Time) {
+	t.UpdatedAt = now
+}
+
+// SetCompleted updates the completion state and its timestamps.
+func (t *Todo) SetCompleted(done bool, now time.Time) {
+	t.Completed = done
+	if done {
+		t.CompletedAt = now
+	} else {
+		t.CompletedAt = time.Time{}
+	}
+	t.Touch(now)
+}
+
+// StartOfWeek returns local midnight of the Monday starting now's week.
+func StartOfWeek(now time.Time) time.Time {
+	d := midnight(now)
+	offset := (int(d.Weekday()) + 6) % 7
+	return d.AddDate(0, 0, -offset)
+}
@@ -0,0 +1,106 @@
+package model
+
+import "time"
+
+// Walk calls fn for every todo in todos and, depth first, for each of its
+// subtasks. Top-level todos have depth 0.
+func Walk(todos []Todo, fn func(t *Todo, depth int)) {
+	walk(todos, 0, fn)
+}
+
+func walk(todos []Todo, depth int, fn func(t *Todo, depth int)) {
+	for i := range todos {
+		fn(&todos[i], depth)
+		walk(todos[i].Subtasks, depth+1, fn)
+	}
+}
+
+// Locate finds the todo with the given ID anywhere in the project. It
+// returns the slice holding it, its index there and its parent, which is
+// nil for top-level todos. The slice is nil when the ID is unknown.
+func (p *Project) Locate(id string) (*[]Todo, int, *Todo) {
+	return locate(&p.Todos, nil, id)
+}
+
+func locate(list *[]Todo, parent *Todo, id string) (*[]Todo, int, *Todo) {
+	for i := range *list {
+		t := &(*list)[i]
+		if t.ID == id {
+			return list, i, parent
+		}
+		if l, j, par := locate(&t.Subtasks, t, id); l != nil {
+			return l, j, par
+		}
+	}
+	return nil, -1, nil
+}
+
+// Indent turns the todo into the last subtask of its previous sibling.
+func (p *Project) Indent(id string) bool {
+	list, i, _ := p.Locate(id)
+	if list == nil || i == 0 {
+		return false
+	}
+	t := (*list)[i]
+	*list = append((*list)[:i], (*list)[i+1:]...)
+	prev := &(*list)[i-1]
+	prev.Subtasks = append(prev.Subtasks, t)
+	prev.Collapsed = false
+	return true
+}
+
+// Outdent moves the todo out of its parent to the position right after it.
+func (p *Project) Outdent(id string) bool {
+	list, i, parent := p.Locate(id)
+	if list == nil || parent == nil {
+		return false
+	}
+	t := (*list)[i]
+	*list = append((*list)[:i], (*list)[i+1:]...)
+	plist, pi, _ := p.Locate(parent.ID)
+	*plist = append(*plist, Todo{})
+	copy((*plist)[pi+2:], (*plist)[pi+1:])
+	(*plist)[pi+1] = t
+	return true
+}
+
+// Progress counts the completed direct subtasks and all direct subtasks.
+func (t Todo) Progress() (done, total int) {
+	for _, sub := range t.Subtasks {
+		if sub.Completed {
+			done++
+		}
+	}
+	return done, len(t.Subtasks)
+}
+
+// SetCompletedTree sets the completion state of the todo and every subtask
+// beneath it. Subtasks already in that state keep their timestamps.
+func (t *Todo) SetCompletedTree(done bool, now time.Time) {
+	if t.Completed != done {
+		t.SetCompleted(done, now)
+	}
+	Walk(t.Subtasks, func(sub *Todo, _ int) {
+		if sub.Completed != done {
+			sub.SetCompleted(done, now)
+		}
+	})
+}
+
+// Copy returns a deep copy of the todo where it and all its subtasks have
+// fresh IDs, for duplicating it next to the original.
+func (t Todo) Copy() Todo {
+	c := t.Clone()
+	c.ID = NewID()
+	Walk(c.Subtasks, func(sub *Todo, _ int) {
+		sub.ID = NewID()
+	})
+	return c
+}
+
+// CountTodos returns the number of todos including subtasks at any depth.
+func CountTodos(todos []Todo) int {
+	n := 0
+	Walk(todos, func(*Todo, int) { n++ })
+	return n
+}
@@ -1,16 +1,31 @@
 package model
 
+import "time"
+
 type Todo struct {
-	Title     string `json:"title"`
-	Completed bool   `json:"completed"`
-	Link      string `json:"link,omitempty"`
+	ID          string    `json:"id"`
+	Title       string    `json:"title"`
+	Completed   bool      `json:"completed"`
+	Link        string    `json:"link,omitempty"`
+	Notes       string    `json:"notes,omitempty"`
+	Due         string    `json:"due,omitempty"`
+	Priority    Priority  `json:"priority,omitempty"`
+	Tags        []string  `json:"tags,omitempty"`
+	CreatedAt   time.Time `json:"created_at,omitzero"`
+	UpdatedAt   time.Time `json:"updated_at,omitzero"`
+	CompletedAt time.Time `json:"completed_at,omitzero"`
+	Collapsed   bool      `json:"collapsed,omitempty"`
+	Subtasks    []Todo    `json:"subtasks,omitempty"`
 }
 
 type Project struct {
-	Name  string `json:"name"`
-	Todos []Todo `json:"todos"`
+	ID    string   `json:"id"`
+	Name  string   `json:"name"`
+	Sort  SortMode `json:"sort,omitempty"`
+	Todos []Todo   `json:"todos"`
 }
 
 type Store struct {
+	Version  int       `json:"version"`
 	Projects []Project `json:"projects"`
 }
@@ -0,0 +1,114 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/danjecu/focusboard-tui/internal/model"
+)
+
+const (
+	// KeepBackups is how many backups are kept next to the data file.
+	KeepBackups = 5
+	// BackupInterval is the minimum age of the newest backup before Save
+	// takes another one, so quick edits do not rotate history away.
+	BackupInterval = 10 * time.Minute
+
+	backupStamp = "20060102-150405"
+	backupExt   = ".bak"
+)
+
+// Backups lists the backup files of path, newest first.
+func Backups(path string) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	prefix := filepath.Base(path) + "."
+	var out []string
+	for _, e := range entries {
+		name := e.Name()
+		if !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, backupExt) {
+			continue
+		}
+		stamp := strings.TrimSuffix(strings.TrimPrefix(name, prefix), backupExt)
+		if _, err := time.Parse(backupStamp, stamp); err == nil {
+			out = append(out, filepath.Join(filepath.Dir(path), name))
+		}
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(out)))
+	return out, nil
+}
+
+// LatestBackup returns the newest backup of path that parses, together with
+// its contents.
+func LatestBackup(path string) (string, model.Store, error) {
+	backups, err := Backups(path)
+	if err != nil {
+		return "", model.Store{}, err
+	}
+	for _, b := range backups {
+		data, err := os.ReadFile(b)
+		if err != nil {
+			continue
+		}
+		if s, _, err := decode(data); err == nil {
+			return b, s, nil
+		}
+	}
+	return "", model.Store{}, os.ErrNotExist
+}
+
+// BackupTime extracts the time a backup was taken from its file name.
+func BackupTime(backupPath string) (time.Time, bool) {
+	name := filepath.Base(backupPath)
+	name = strings.TrimSuffix(name, backupExt)
+	i := strings.LastIndex(name, ".")
+	if i < 0 {
+		return time.Time{}, false
+	}
+	t, err := time.ParseInLocation(backupStamp, name[i+1:], time.Local)
+	return t, err == nil
+}
+
+// backup copies the current file aside unless a recent backup exists;
+// force skips the BackupInterval check.
+func backup(path string, force bool) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if len(strings.TrimSpace(string(data))) == 0 {
+		return nil
+	}
+
+	backups, err := Backups(path)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	if len(backups) > 0 && !force {
+		if last, ok := BackupTime(backups[0]); ok && now.Sub(last) < BackupInterval {
+			return nil
+		}
+	}
+
+	name := path + "." + now.Format(backupStamp) + backupExt
+	if err := writeAtomic(name, data); err != nil {
+		return err
+	}
+	backups = append([]string{name}, backups...)
+	for _, old := range backups[min(len(backups), KeepBackups):] {
+		os.Remove(old)
+	}
+	return nil
+}
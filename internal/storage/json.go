@@ -1,13 +1,30 @@
 package storage
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/danjecu/focusboard-tui/internal/model"
 )
 
+// CorruptError is returned by Load when the data file exists but cannot be
+// parsed.
+type CorruptError struct {
+	Path string
+	Err  error
+}
+
+func (e *CorruptError) Error() string {
+	return fmt.Sprintf("invalid data file: %v", e.Err)
+}
+
+func (e *CorruptError) Unwrap() error { return e.Err }
+
 func Load(path string) (model.Store, error) {
 	var s model.Store
 	data, err := os.ReadFile(path)
@@ -17,11 +34,37 @@ func Load(path string) (model.Store, error) {
 		}
 		return s, err
 	}
+	s, upgraded, err := decode(data)
+	var verr *VersionError
+	if errors.As(err, &verr) {
+		return s, err
+	}
+	if err != nil {
+		return s, &CorruptError{Path: path, Err: err}
+	}
+
+	// Write upgrades back straight away so generated IDs stay stable
+	// between runs, keeping the original as a backup. A read-only
+	// location still loads fine.
+	if upgraded {
+		save(path, s, true)
+	}
+	return s, nil
+}
+
+// decode parses and migrates data. upgraded reports whether the result
+// differs from what is stored, i.e. a migration ran or IDs were filled in.
+func decode(data []byte) (s model.Store, upgraded bool, err error) {
+	s = model.Store{Version: SchemaVersion}
 	if len(strings.TrimSpace(string(data))) == 0 {
-		return s, nil
+		return s, false, nil
 	}
-	if err := json.Unmarshal(data, &s); err != nil {
-		return s, err
+	migrated, err := migrate(data)
+	if err != nil {
+		return model.Store{}, false, err
+	}
+	if err := json.Unmarshal(migrated, &s); err != nil {
+		return model.Store{}, false, err
 	}
 
 	for i := range s.Projects {
@@ -29,13 +72,63 @@ func Load(path string) (model.Store, error) {
 			s.Projects[i].Todos = []model.Todo{}
 		}
 	}
-	return s, nil
+	filled := s.EnsureIDs()
+	return s, filled || !bytes.Equal(migrated, data), nil
 }
 
+// Save writes the store atomically: the data goes to a temporary file in
+// the same directory, is synced, and then renamed over path. The previous
+// file is kept as a rotating backup first.
 func Save(path string, s model.Store) error {
+	return save(path, s, false)
+}
+
+func save(path string, s model.Store, forceBackup bool) error {
+	s.Version = SchemaVersion
 	data, err := json.MarshalIndent(s, "", "  ")
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(path, data, 0o644)
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	if err := backup(path, forceBackup); err != nil {
+		return fmt.Errorf("backup: %w", err)
+	}
+	return writeAtomic(path, data)
+}
+
+func writeAtomic(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+	defer os.Remove(tmpName)
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmpName, 0o644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpName, path); err != nil {
+		return err
+	}
+
+	if d, err := os.Open(dir); err == nil {
+		d.Sync()
+		d.Close()
+	}
+	return nil
 }
@@ -0,0 +1,120 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/danjecu/focusboard-tui/internal/model"
+)
+
+// SchemaVersion is the version written by Save. Files without a version
+// field are version 0.
+const SchemaVersion = 3
+
+// VersionError is returned for files written by a newer focusboard.
+type VersionError struct {
+	Found     int
+	Supported int
+}
+
+func (e *VersionError) Error() string {
+	return fmt.Sprintf("written by a newer focusboard (schema v%d, this build supports up to v%d)", e.Found, e.Supported)
+}
+
+type document = map[string]any
+
+// migrations[n] upgrades a document from version n to n+1. Append a step
+// and bump SchemaVersion whenever the persisted shape changes.
+var migrations = []func(document) error{
+	0: migrateV0,
+	1: migrateV1,
+	2: migrateV2,
+}
+
+// migrateV0 upgrades unversioned files. Their shape is already what v1
+// expects; missing or null todo lists are normalised to empty ones.
+func migrateV0(doc document) error {
+	projects, _ := doc["projects"].([]any)
+	for _, p := range projects {
+		project, ok := p.(document)
+		if !ok {
+			return fmt.Errorf("project is not an object")
+		}
+		if project["todos"] == nil {
+			project["todos"] = []any{}
+		}
+	}
+	return nil
+}
+
+// migrateV1 backfills the stable IDs introduced in v2.
+func migrateV1(doc document) error {
+	projects, _ := doc["projects"].([]any)
+	for _, p := range projects {
+		project, ok := p.(document)
+		if !ok {
+			return fmt.Errorf("project is not an object")
+		}
+		fillID(project)
+		todos, _ := project["todos"].([]any)
+		for _, t := range todos {
+			todo, ok := t.(document)
+			if !ok {
+				return fmt.Errorf("todo is not an object")
+			}
+			fillID(todo)
+		}
+	}
+	return nil
+}
+
+// migrateV2 has nothing to convert. v3 adds optional fields (timestamps,
+// nested subtasks, collapsed state and notes) that older builds would
+// silently drop on their next save, so the bump makes them refuse the file.
+func migrateV2(doc document) error {
+	return nil
+}
+
+func fillID(obj document) {
+	if id, _ := obj["id"].(string); id == "" {
+		obj["id"] = model.NewID()
+	}
+}
+
+// migrate brings raw JSON up to SchemaVersion.
+func migrate(data []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var doc document
+	if err := dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	version := 0
+	if v, ok := doc["version"]; ok {
+		n, ok := v.(json.Number)
+		if !ok {
+			return nil, fmt.Errorf("version is not a number")
+		}
+		i, err := n.Int64()
+		if err != nil || i < 0 {
+			return nil, fmt.Errorf("invalid version %s", n)
+		}
+		version = int(i)
+	}
+	if version > SchemaVersion {
+		return nil, &VersionError{Found: version, Supported: SchemaVersion}
+	}
+	if version == SchemaVersion {
+		return data, nil
+	}
+
+	for v := version; v < SchemaVersion; v++ {
+		if err := migrations[v](doc); err != nil {
+			return nil, fmt.Errorf("migrating from v%d: %w", v, err)
+		}
+		doc["version"] = v + 1
+	}
+	return json.Marshal(doc)
+}
@@ -0,0 +1,143 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/danjecu/focusboard-tui/internal/model"
+)
+
+// copyFixture copies testdata/name into a temporary directory, since Load
+// writes upgraded files back.
+func copyFixture(t *testing.T, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "todos.json")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadUpgradesHistoricalSchemas(t *testing.T) {
+	for _, fixture := range []string{"v0.json", "v1.json", "v2.json"} {
+		t.Run(fixture, func(t *testing.T) {
+			path := copyFixture(t, fixture)
+			s, err := Load(path)
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+			if s.Version != SchemaVersion {
+				t.Errorf("version = %d, want %d", s.Version, SchemaVersion)
+			}
+			if len(s.Projects) != 2 {
+				t.Fatalf("got %d projects, want 2", len(s.Projects))
+			}
+			work, empty := s.Projects[0], s.Projects[1]
+			if work.Name != "Work" || empty.Name != "Empty" {
+				t.Errorf("project names = %q, %q", work.Name, empty.Name)
+			}
+			if empty.Todos == nil {
+				t.Error("empty project has nil todos")
+			}
+			if len(work.Todos) != 2 {
+				t.Fatalf("got %d todos, want 2", len(work.Todos))
+			}
+			report, release := work.Todos[0], work.Todos[1]
+			if report.Title != "Write report" || report.Completed || report.Link != "https://example.com/report" {
+				t.Errorf("first todo = %+v", report)
+			}
+			if release.Title != "Ship release" || !release.Completed {
+				t.Errorf("second todo = %+v", release)
+			}
+
+			ids := map[string]bool{}
+			for _, id := range []string{work.ID, empty.ID, report.ID, release.ID} {
+				if id == "" || ids[id] {
+					t.Errorf("missing or duplicate id %q", id)
+				}
+				ids[id] = true
+			}
+			if fixture == "v2.json" && (work.ID != "p1" || report.ID != "t1") {
+				t.Errorf("existing ids replaced: %q, %q", work.ID, report.ID)
+			}
+
+			// The upgrade is written back with the current version, and
+			// loading it again keeps the generated IDs.
+			var onDisk struct {
+				Version int `json:"version"`
+			}
+			data, _ := os.ReadFile(path)
+			if err := json.Unmarshal(data, &onDisk); err != nil {
+				t.Fatal(err)
+			}
+			if onDisk.Version != SchemaVersion {
+				t.Errorf("file version = %d, want %d", onDisk.Version, SchemaVersion)
+			}
+			again, err := Load(path)
+			if err != nil {
+				t.Fatalf("second Load: %v", err)
+			}
+			if again.Projects[0].Todos[0].ID != report.ID {
+				t.Errorf("id changed between loads: %q then %q", report.ID, again.Projects[0].Todos[0].ID)
+			}
+		})
+	}
+}
+
+func TestLoadKeepsFieldsFromV1(t *testing.T) {
+	s, err := Load(copyFixture(t, "v1.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	work := s.Projects[0]
+	if work.Sort != model.SortPriority {
+		t.Errorf("sort = %q, want priority", work.Sort)
+	}
+	report := work.Todos[0]
+	if report.Due != "2025-06-30" || report.Priority != model.PriorityHigh || len(report.Tags) != 1 || report.Tags[0] != "docs" {
+		t.Errorf("todo = %+v", report)
+	}
+}
+
+// v1-created-at.json holds the creation time that later files carry
+// next to the v1 fields; the migrations must pass it through untouched.
+func TestLoadKeepsCreatedAtThroughMigrations(t *testing.T) {
+	s, err := Load(copyFixture(t, "v1-created-at.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC)
+	if got := s.Projects[0].Todos[0].CreatedAt; !got.Equal(want) {
+		t.Errorf("created_at = %v, want %v", got, want)
+	}
+}
+
+func TestLoadRefusesNewerSchema(t *testing.T) {
+	path := copyFixture(t, "newer.json")
+	before, _ := os.ReadFile(path)
+
+	_, err := Load(path)
+	var verr *VersionError
+	if !errors.As(err, &verr) {
+		t.Fatalf("err = %v, want a VersionError", err)
+	}
+	if verr.Found != 99 || verr.Supported != SchemaVersion {
+		t.Errorf("VersionError = %+v", verr)
+	}
+
+	after, _ := os.ReadFile(path)
+	if string(after) != string(before) {
+		t.Error("a newer file was rewritten")
+	}
+	if backups, _ := filepath.Glob(path + ".*.bak"); len(backups) > 0 {
+		t.Errorf("unexpected backups %v", backups)
+	}
+}
@@ -0,0 +1,116 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+const (
+	// EnvFile names the environment variable that overrides the data file.
+	EnvFile = "FOCUSBOARD_FILE"
+	// RepoFile is the per-repository board looked up from the working
+	// directory towards the enclosing git repository root.
+	RepoFile = ".focusboard.json"
+	// LegacyFile is the data file older versions kept in the working
+	// directory.
+	LegacyFile = "todos.json"
+)
+
+// ResolvePath picks the data file: an explicit path wins, then
+// $FOCUSBOARD_FILE, then a .focusboard.json inside the current git
+// repository, and finally $XDG_DATA_HOME/focusboard/todos.json. Until that
+// file exists, a todos.json left in the working directory by an older
+// version is used instead so upgrading does not open an empty board.
+func ResolvePath(explicit string) (string, error) {
+	if explicit != "" {
+		return explicit, nil
+	}
+	if env := os.Getenv(EnvFile); env != "" {
+		return env, nil
+	}
+	if wd, err := os.Getwd(); err == nil {
+		if p, ok := findRepoBoard(wd); ok {
+			return p, nil
+		}
+	}
+	def, err := defaultPath()
+	if err != nil {
+		return "", err
+	}
+	if legacy, ok := legacyPath(def); ok {
+		return legacy, nil
+	}
+	return def, nil
+}
+
+// LegacyNotice returns a hint to move the board to the default location
+// when path is a working directory todos.json picked up by ResolvePath.
+func LegacyNotice(path string) string {
+	def, err := defaultPath()
+	if err != nil {
+		return ""
+	}
+	if legacy, ok := legacyPath(def); !ok || legacy != path {
+		return ""
+	}
+	return fmt.Sprintf("using %s from an older focusboard; move it to %s (or pass --file) to keep using it from anywhere", path, def)
+}
+
+func legacyPath(def string) (string, bool) {
+	if _, err := os.Stat(def); !errors.Is(err, fs.ErrNotExist) {
+		return "", false
+	}
+	legacy, err := filepath.Abs(LegacyFile)
+	if err != nil {
+		return "", false
+	}
+	if info, err := os.Stat(legacy); err != nil || info.IsDir() {
+		return "", false
+	}
+	return legacy, true
+}
+
+func findRepoBoard(dir string) (string, bool) {
+	root, ok := repoRoot(dir)
+	if !ok {
+		return "", false
+	}
+	for {
+		candidate := filepath.Join(dir, RepoFile)
+		if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+			return candidate, true
+		}
+		if dir == root {
+			return "", false
+		}
+		dir = filepath.Dir(dir)
+	}
+}
+
+func repoRoot(dir string) (string, bool) {
+	for {
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return dir, true
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+func defaultPath() (string, error) {
+	base := os.Getenv("XDG_DATA_HOME")
+	if base == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", errors.New("cannot determine a data directory; set --file or " + EnvFile)
+		}
+		base = filepath.Join(home, ".local", "share")
+	}
+	return filepath.Join(base, "focusboard", "todos.json"), nil
+}
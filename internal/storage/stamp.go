@@ -0,0 +1,32 @@
+package storage
+
+import (
+	"os"
+	"time"
+)
+
+// Stamp identifies a version of the data file on disk. The zero Stamp means
+// the file does not exist.
+type Stamp struct {
+	ModTime time.Time
+	Size    int64
+}
+
+func (s Stamp) Equal(o Stamp) bool {
+	return s.ModTime.Equal(o.ModTime) && s.Size == o.Size
+}
+
+func (s Stamp) Exists() bool {
+	return !s.ModTime.IsZero()
+}
+
+func StatFile(path string) (Stamp, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return Stamp{}, nil
+		}
+		return Stamp{}, err
+	}
+	return Stamp{ModTime: info.ModTime(), Size: info.Size()}, nil
+}
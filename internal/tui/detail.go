@@ -0,0 +1,168 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/danjecu/focusboard-tui/internal/model"
+)
+
+const (
+	notesCharLimit = 4000
+	notesHeight    = 10
+	factsWidth     = 38
+)
+
+func (m *Model) beginNotes() {
+	t := m.selectedTodo()
+	if t == nil {
+		m.status = "No todo to write notes for"
+		m.statusErr = true
+		return
+	}
+	m.mode = modeInput
+	m.target = targetSetNotes
+	m.input.CharLimit = notesCharLimit
+	m.input.SetHeight(notesHeight)
+	m.input.SetValue(t.Notes)
+	m.input.Placeholder = "Notes (markdown)"
+	m.input.Focus()
+	m.status = "Edit notes"
+	m.statusErr = false
+}
+
+func (m *Model) commitNotes(raw string) {
+	notes := strings.TrimRight(strings.TrimLeft(raw, "\n"), " \t\n")
+	if ref, ok := m.selectedRef(); ok {
+		t := m.todoAt(ref)
+		if t.Notes == notes {
+			m.status = "Notes unchanged"
+		} else {
+			m.checkpoint(fmt.Sprintf("edit notes of %q", t.Title))
+			t.Notes = notes
+			t.Touch(time.Now())
+			if notes == "" {
+				m.status = "Notes cleared"
+			} else {
+				m.status = "Notes saved"
+			}
+		}
+		m.statusErr = false
+	}
+	m.mode = modeNormal
+	m.target = targetNone
+	m.input.Blur()
+	m.resetInputSize()
+	m.persist()
+}
+
+// resetInputSize undoes the larger editor set up for notes.
+func (m *Model) resetInputSize() {
+	m.input.CharLimit = inputCharLimit
+	m.input.SetHeight(inputHeight)
+}
+
+func (m *Model) toggleDetail() {
+	m.showDetail = !m.showDetail
+	if m.showDetail {
+		m.status = "Detail pane shown"
+	} else {
+		m.status = "Detail pane hidden"
+	}
+	m.statusErr = false
+}
+
+// detailHeight is the inner height of the detail pane, or 0 when hidden.
+func (m Model) detailHeight() int {
+	if !m.showDetail {
+		return 0
+	}
+	return min(max(m.height/3, 4), 14)
+}
+
+// detailLines shows the selected todo's fields next to its rendered notes,
+// or stacked above them when the terminal is narrow.
+func (m Model) detailLines(width int) []string {
+	ref, ok := m.selectedRef()
+	if !ok {
+		return []string{descStyle.Render("Select a todo to see its details.")}
+	}
+	t := m.todoAt(ref)
+	facts := m.todoFacts(ref, *t)
+
+	var notes []string
+	if t.Notes == "" {
+		notes = []string{descStyle.Render("No notes. Press i to write some.")}
+	}
+
+	if width < 2*factsWidth {
+		if t.Notes != "" {
+			notes = renderMarkdown(t.Notes, width)
+		}
+		return append(append(facts, ""), notes...)
+	}
+
+	notesWidth := width - factsWidth - 2
+	if t.Notes != "" {
+		notes = renderMarkdown(t.Notes, notesWidth)
+	}
+	for i := range facts {
+		facts[i] = truncateLine(facts[i], factsWidth)
+	}
+	for i := range notes {
+		notes[i] = truncateLine(notes[i], notesWidth)
+	}
+	left := lipgloss.NewStyle().Width(factsWidth + 2).Render(strings.Join(facts, "\n"))
+	return strings.Split(lipgloss.JoinHorizontal(lipgloss.Top, left, strings.Join(notes, "\n")), "\n")
+}
+
+func (m Model) todoFacts(ref todoRef, t model.Todo) []string {
+	now := time.Now()
+	style := selectedStyle
+	if t.Completed {
+		style = completedStyle
+	}
+	lines := []string{style.Render(t.Title)}
+	row := func(label, value string) {
+		lines = append(lines, inputLabelStyle.Render(fmt.Sprintf("%-10s", label))+value)
+	}
+
+	if p := m.store.Project(ref.project); p != nil {
+		row("Project", normalStyle.Render(p.Name))
+	}
+	if t.Priority != model.PriorityNone {
+		row("Priority", priorityMarker(t)+normalStyle.Render(t.Priority.String()))
+	}
+	if t.Due != "" {
+		due := normalStyle.Render(t.Due)
+		if !t.Completed {
+			due += " " + dueLabel(t, now)
+		}
+		row("Due", due)
+	}
+	if len(t.Tags) > 0 {
+		row("Tags", tagStyle.Render(formatTags(t.Tags)))
+	}
+	if t.Link != "" {
+		row("Link", normalStyle.Render(t.Link))
+	}
+	if done, total := t.Progress(); total > 0 {
+		row("Subtasks", normalStyle.Render(fmt.Sprintf("%d/%d done", done, total)))
+	}
+	for _, ts := range []struct {
+		label string
+		at    time.Time
+	}{
+		{"Created", t.CreatedAt},
+		{"Updated", t.UpdatedAt},
+		{"Completed", t.CompletedAt},
+	} {
+		if !ts.at.IsZero() {
+			row(ts.label, descStyle.Render(ts.at.Local().Format("2006-01-02 15:04")+" · "+relativeAge(ts.at, now)))
+		}
+	}
+	return lines
+}
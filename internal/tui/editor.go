@@ -0,0 +1,298 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/danjecu/focusboard-tui/internal/model"
+)
+
+// editorDoneMsg reports that the external editor exited. todo is empty
+// when a project was being edited.
+type editorDoneMsg struct {
+	path    string
+	project string
+	todo    string
+	err     error
+}
+
+// editorFile remembers a temporary file that failed to parse, so pressing
+// E again on the same item reopens it with the user's edits intact.
+type editorFile struct {
+	path    string
+	project string
+	todo    string
+}
+
+func editorCommand() []string {
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		if fields := strings.Fields(os.Getenv(env)); len(fields) > 0 {
+			return fields
+		}
+	}
+	return []string{"vi"}
+}
+
+func (m *Model) beginExternalEdit() tea.Cmd {
+	var target editorFile
+	var doc string
+	if m.focus == focusProjects {
+		p := m.currentProject()
+		if p == nil {
+			m.status = "No project to edit"
+			m.statusErr = true
+			return nil
+		}
+		target = editorFile{project: p.ID}
+		doc = formatProjectDoc(*p)
+	} else {
+		ref, ok := m.selectedRef()
+		if !ok {
+			m.status = "No todo to edit"
+			m.statusErr = true
+			return nil
+		}
+		target = editorFile{project: ref.project, todo: ref.todo}
+		doc = formatTodoDoc(*m.todoAt(ref))
+	}
+
+	if m.editorFile.path != "" && m.editorFile.project == target.project && m.editorFile.todo == target.todo {
+		target.path = m.editorFile.path
+	} else {
+		m.discardEditorFile()
+		f, err := os.CreateTemp("", "focusboard-*.md")
+		if err != nil {
+			m.status = fmt.Sprintf("creating temp file: %v", err)
+			m.statusErr = true
+			return nil
+		}
+		_, err = f.WriteString(doc)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			os.Remove(f.Name())
+			m.status = fmt.Sprintf("writing temp file: %v", err)
+			m.statusErr = true
+			return nil
+		}
+		target.path = f.Name()
+	}
+	m.editorFile = editorFile{}
+
+	args := append(editorCommand(), target.path)
+	cmd := exec.Command(args[0], args[1:]...)
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		return editorDoneMsg{path: target.path, project: target.project, todo: target.todo, err: err}
+	})
+}
+
+func (m *Model) discardEditorFile() {
+	if m.editorFile.path != "" {
+		os.Remove(m.editorFile.path)
+		m.editorFile = editorFile{}
+	}
+}
+
+func (m *Model) finishExternalEdit(msg editorDoneMsg) {
+	if msg.err != nil {
+		os.Remove(msg.path)
+		m.status = fmt.Sprintf("editor failed: %v", msg.err)
+		m.statusErr = true
+		return
+	}
+	data, err := os.ReadFile(msg.path)
+	if err != nil {
+		os.Remove(msg.path)
+		m.status = fmt.Sprintf("reading edited file: %v", err)
+		m.statusErr = true
+		return
+	}
+
+	if msg.todo == "" {
+		err = m.applyProjectDoc(msg.project, string(data))
+	} else {
+		err = m.applyTodoDoc(todoRef{project: msg.project, todo: msg.todo}, string(data))
+	}
+	if err != nil {
+		m.editorFile = editorFile{path: msg.path, project: msg.project, todo: msg.todo}
+		m.status = err.Error() + " (press E to fix it)"
+		m.statusErr = true
+		return
+	}
+	os.Remove(msg.path)
+}
+
+func formatTodoDoc(t model.Todo) string {
+	var b strings.Builder
+	field := func(key, value string) {
+		b.WriteString(strings.TrimRight(key+": "+value, " ") + "\n")
+	}
+	b.WriteString("---\n")
+	field("title", t.Title)
+	field("completed", fmt.Sprint(t.Completed))
+	field("priority", t.Priority.String())
+	field("due", t.Due)
+	field("tags", strings.Join(t.Tags, ", "))
+	field("link", t.Link)
+	b.WriteString("---\n")
+	if t.Notes != "" {
+		b.WriteString(t.Notes + "\n")
+	}
+	return b.String()
+}
+
+func formatProjectDoc(p model.Project) string {
+	return fmt.Sprintf("---\nname: %s\nsort: %s\n---\n", p.Name, p.Sort)
+}
+
+// parseDoc splits a document into its front matter fields and the body
+// after the closing "---". Blank lines and lines starting with '#' inside
+// the front matter are ignored.
+func parseDoc(text string, allowed []string) (map[string]string, string, error) {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	start := 0
+	for start < len(lines) && strings.TrimSpace(lines[start]) == "" {
+		start++
+	}
+	if start == len(lines) || strings.TrimSpace(lines[start]) != "---" {
+		return nil, "", fmt.Errorf("line %d: expected --- to open the front matter", start+1)
+	}
+
+	fields := map[string]string{}
+	for i := start + 1; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if line == "---" {
+			body := strings.Join(lines[i+1:], "\n")
+			return fields, strings.TrimRight(strings.TrimLeft(body, "\n"), " \t\n"), nil
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, "", fmt.Errorf("line %d: expected \"field: value\"", i+1)
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		if !containsString(allowed, key) {
+			return nil, "", fmt.Errorf("line %d: unknown field %q", i+1, key)
+		}
+		if _, dup := fields[key]; dup {
+			return nil, "", fmt.Errorf("line %d: %s given twice", i+1, key)
+		}
+		fields[key] = strings.TrimSpace(value)
+	}
+	return nil, "", fmt.Errorf("front matter is not closed with ---")
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func parseBool(s string) (bool, error) {
+	switch strings.ToLower(s) {
+	case "true", "yes", "y", "x", "done":
+		return true, nil
+	case "", "false", "no", "n":
+		return false, nil
+	}
+	return false, fmt.Errorf("completed: expected true or false, got %q", s)
+}
+
+func (m *Model) applyTodoDoc(ref todoRef, text string) error {
+	fields, notes, err := parseDoc(text, []string{"title", "completed", "priority", "due", "tags", "link"})
+	if err != nil {
+		return err
+	}
+	title := fields["title"]
+	if title == "" {
+		return fmt.Errorf("title must not be empty")
+	}
+	completed, err := parseBool(fields["completed"])
+	if err != nil {
+		return err
+	}
+	priority, err := model.ParsePriority(strings.ToLower(fields["priority"]))
+	if err != nil {
+		return fmt.Errorf("priority: %v", err)
+	}
+	now := time.Now()
+	due, err := model.ParseDue(fields["due"], now)
+	if err != nil {
+		return fmt.Errorf("due: %v", err)
+	}
+
+	t := m.todoAt(ref)
+	if t == nil {
+		return fmt.Errorf("the todo no longer exists")
+	}
+	edited := t.Clone()
+	edited.Title = title
+	edited.Priority = priority
+	edited.Due = due
+	edited.Tags = model.ParseTags(fields["tags"])
+	edited.Link = fields["link"]
+	edited.Notes = notes
+	if completed != edited.Completed {
+		edited.SetCompleted(completed, now)
+	}
+	if formatTodoDoc(edited) == formatTodoDoc(*t) {
+		m.status = "No changes"
+		m.statusErr = false
+		return nil
+	}
+
+	m.checkpoint(fmt.Sprintf("edit %q in editor", t.Title))
+	edited.Touch(now)
+	*t = edited
+	m.selectTodo(ref)
+	m.status = "Todo updated"
+	m.statusErr = false
+	m.persist()
+	return nil
+}
+
+func (m *Model) applyProjectDoc(id, text string) error {
+	fields, body, err := parseDoc(text, []string{"name", "sort"})
+	if err != nil {
+		return err
+	}
+	if body != "" {
+		return fmt.Errorf("projects have no notes; remove the text after ---")
+	}
+	name := fields["name"]
+	if name == "" {
+		return fmt.Errorf("name must not be empty")
+	}
+	sort, err := model.ParseSortMode(strings.ToLower(fields["sort"]))
+	if err != nil {
+		return fmt.Errorf("sort: %v", err)
+	}
+
+	p := m.store.Project(id)
+	if p == nil {
+		return fmt.Errorf("the project no longer exists")
+	}
+	if p.Name == name && p.Sort == sort {
+		m.status = "No changes"
+		m.statusErr = false
+		return nil
+	}
+	m.checkpoint(fmt.Sprintf("edit project %q in editor", p.Name))
+	p.Name = name
+	p.Sort = sort
+	m.status = "Project updated"
+	m.statusErr = false
+	m.persist()
+	return nil
+}
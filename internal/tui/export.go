@@ -0,0 +1,113 @@
+package tui
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/atotto/clipboard"
+
+	"github.com/danjecu/focusboard-tui/internal/format"
+	"github.com/danjecu/focusboard-tui/internal/model"
+)
+
+// exportScope is the open project while browsing its todos, otherwise the
+// whole board.
+func (m *Model) exportScope() ([]model.Project, string) {
+	if p := m.currentProject(); p != nil && m.focus == focusTodos && m.view == viewProject {
+		return []model.Project{*p}, p.Name
+	}
+	return m.store.Projects, "board"
+}
+
+// exportMarkdown leaves completed todos out like the export command does,
+// unless they were switched on in the save prompt.
+func (m *Model) exportMarkdown() ([]byte, string, error) {
+	projects, scope := m.exportScope()
+	var buf bytes.Buffer
+	err := format.WriteMarkdown(&buf, projects, format.Options{Completed: m.exportDone})
+	return buf.Bytes(), scope, err
+}
+
+func (m *Model) copyMarkdown() {
+	data, scope, err := m.exportMarkdown()
+	if err == nil {
+		err = clipboard.WriteAll(string(data))
+	}
+	if err != nil {
+		m.status = fmt.Sprintf("copying markdown: %v", err)
+		m.statusErr = true
+		return
+	}
+	m.status = fmt.Sprintf("Copied %s as markdown", scope)
+	m.statusErr = false
+}
+
+func (m *Model) beginExportFile() {
+	_, scope := m.exportScope()
+	name := "focusboard.md"
+	if scope != "board" {
+		name = slug(scope) + ".md"
+	}
+	m.mode = modeInput
+	m.target = targetExportFile
+	m.input.SetValue(name)
+	m.input.Placeholder = "File to write"
+	m.input.Focus()
+	m.status = fmt.Sprintf("Export %s as markdown", scope)
+	m.statusErr = false
+}
+
+func (m Model) exportHint() string {
+	if m.exportDone {
+		return "completed todos included · tab leaves them out"
+	}
+	return "completed todos left out · tab includes them"
+}
+
+// commitExportFile writes the markdown export and reports whether it
+// succeeded, so a bad path keeps the prompt open.
+func (m *Model) commitExportFile(path string) bool {
+	if path == "" {
+		m.status = "No file given"
+		m.statusErr = true
+		return false
+	}
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, rest)
+		}
+	}
+	data, scope, err := m.exportMarkdown()
+	if err == nil {
+		err = os.WriteFile(path, data, 0o644)
+	}
+	if err != nil {
+		m.status = fmt.Sprintf("exporting: %v", err)
+		m.statusErr = true
+		return false
+	}
+	m.status = fmt.Sprintf("Exported %s to %s", scope, path)
+	m.statusErr = false
+	return true
+}
+
+func slug(s string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(s) {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' {
+			b.WriteRune(r)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	if out := strings.TrimSuffix(b.String(), "-"); out != "" {
+		return out
+	}
+	return "project"
+}
@@ -0,0 +1,44 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/danjecu/focusboard-tui/internal/model"
+)
+
+func TestExportFileCompletedToggle(t *testing.T) {
+	dir := t.TempDir()
+	m := New(filepath.Join(dir, "todos.json"))
+	m.store.Projects = []model.Project{{Name: "Work", Todos: []model.Todo{
+		{Title: "Open"},
+		{Title: "Finished", Completed: true},
+	}}}
+
+	export := func(m Model, name string, keys ...tea.KeyMsg) string {
+		t.Helper()
+		m.beginExportFile()
+		m.input.SetValue(filepath.Join(dir, name))
+		var next tea.Model = m
+		for _, k := range append(keys, tea.KeyMsg{Type: tea.KeyEnter}) {
+			next, _ = next.Update(k)
+		}
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+
+	// Like the export command, completed todos are left out by default.
+	if out := export(m, "default.md"); !strings.Contains(out, "Open") || strings.Contains(out, "Finished") {
+		t.Errorf("default export:\n%s", out)
+	}
+	if out := export(m, "all.md", tea.KeyMsg{Type: tea.KeyTab}); !strings.Contains(out, "Finished") {
+		t.Errorf("export with completed todos:\n%s", out)
+	}
+}
@@ -0,0 +1,96 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+type helpEntry struct {
+	key  string
+	desc string
+}
+
+var helpEntries = []helpEntry{
+	{"j/k ↓/↑", "move cursor"},
+	{"ctrl+h/l ←/→", "switch pane"},
+	{"pgup/pgdown", "page up/down"},
+	{"ctrl+u/ctrl+d", "half page up/down"},
+	{"g/G", "first/last item"},
+	{"enter", "open project / toggle todo"},
+	{"K/J alt+↑/↓", "move item up/down"},
+	{"T/B", "move item to top/bottom"},
+	{"a", "add"},
+	{"A", "add subtask"},
+	{"e", "edit"},
+	{"E", "edit in $EDITOR"},
+	{"d", "delete"},
+	{"tab/shift+tab", "indent/outdent todo"},
+	{"z", "fold/unfold subtasks"},
+	{"X", "toggle todo with subtasks"},
+	{"l", "set link"},
+	{"o", "open link"},
+	{"i", "edit notes"},
+	{"v", "toggle detail pane"},
+	{"t", "set due date"},
+	{"m/c", "move/copy todo to project"},
+	{"D", "due soon view"},
+	{"W", "completed this week view"},
+	{"p/P", "raise/lower priority"},
+	{"s", "cycle sort order"},
+	{"#", "edit tags"},
+	{"f", "filter by tag"},
+	{"/", "search titles and links"},
+	{"n/N", "next/previous match"},
+	{"esc", "clear search / back to project"},
+	{"y/Y", "copy/save as markdown"},
+	{"u", "undo"},
+	{"ctrl+r", "redo"},
+	{"?", "this help"},
+	{"q", "quit"},
+}
+
+func helpCells() ([]string, int) {
+	keyWidth := 0
+	for _, e := range helpEntries {
+		if w := lipgloss.Width(e.key); w > keyWidth {
+			keyWidth = w
+		}
+	}
+
+	cells := make([]string, len(helpEntries))
+	cellWidth := 0
+	for i, e := range helpEntries {
+		key := e.key + strings.Repeat(" ", keyWidth-lipgloss.Width(e.key))
+		cells[i] = inputLabelStyle.Render(key) + "  " + descStyle.Render(e.desc)
+		if w := lipgloss.Width(cells[i]); w > cellWidth {
+			cellWidth = w
+		}
+	}
+	return cells, cellWidth
+}
+
+func (m Model) helpWidth() int {
+	_, cellWidth := helpCells()
+	w := 2*cellWidth + 4 + 4
+	if w > m.width-4 {
+		w = m.width - 4
+	}
+	return w
+}
+
+func (m Model) helpBody() string {
+	cells, cellWidth := helpCells()
+	half := (len(cells) + 1) / 2
+
+	rows := make([]string, 0, half+2)
+	for i := 0; i < half; i++ {
+		row := cells[i]
+		if j := i + half; j < len(cells) {
+			row += strings.Repeat(" ", cellWidth-lipgloss.Width(cells[i])+4) + cells[j]
+		}
+		rows = append(rows, row)
+	}
+	rows = append(rows, "", descStyle.Render("press any key to close"))
+	return strings.Join(rows, "\n")
+}
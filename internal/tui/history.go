@@ -0,0 +1,58 @@
+package tui
+
+import "github.com/danjecu/focusboard-tui/internal/model"
+
+const maxHistory = 100
+
+type snapshot struct {
+	store model.Store
+	desc  string
+}
+
+// checkpoint records the store as it is before a mutation described by
+// desc. Any redo history is dropped, as it no longer follows on.
+func (m *Model) checkpoint(desc string) {
+	m.undoStack = append(m.undoStack, snapshot{store: m.store.Clone(), desc: desc})
+	if len(m.undoStack) > maxHistory {
+		m.undoStack = m.undoStack[len(m.undoStack)-maxHistory:]
+	}
+	m.redoStack = nil
+}
+
+func (m *Model) undo() {
+	if len(m.undoStack) == 0 {
+		m.status = "Nothing to undo"
+		m.statusErr = true
+		return
+	}
+	last := m.undoStack[len(m.undoStack)-1]
+	m.undoStack = m.undoStack[:len(m.undoStack)-1]
+	m.redoStack = append(m.redoStack, snapshot{store: m.store.Clone(), desc: last.desc})
+	m.restore(last.store)
+	m.status = "Undid: " + last.desc
+	m.statusErr = false
+	m.persist()
+}
+
+func (m *Model) redo() {
+	if len(m.redoStack) == 0 {
+		m.status = "Nothing to redo"
+		m.statusErr = true
+		return
+	}
+	next := m.redoStack[len(m.redoStack)-1]
+	m.redoStack = m.redoStack[:len(m.redoStack)-1]
+	m.undoStack = append(m.undoStack, snapshot{store: m.store.Clone(), desc: next.desc})
+	m.restore(next.store)
+	m.status = "Redid: " + next.desc
+	m.statusErr = false
+	m.persist()
+}
+
+func (m *Model) restore(s model.Store) {
+	m.store = s
+	if len(m.store.Projects) == 0 {
+		m.focus = focusProjects
+	}
+	m.clampCursors()
+}
@@ -0,0 +1,135 @@
+package tui
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+var (
+	headingStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.AdaptiveColor{Light: "25", Dark: "212"}).
+			Bold(true)
+
+	codeStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.AdaptiveColor{Light: "130", Dark: "179"})
+)
+
+// renderMarkdown draws the small part of markdown that shows up in notes:
+// headings, bullet and task lists, quotes, fenced code and inline
+// emphasis, code and links. Lines are wrapped to width with list items
+// indented under their marker.
+func renderMarkdown(text string, width int) []string {
+	var lines []string
+	inCode := false
+	for _, raw := range strings.Split(text, "\n") {
+		raw = strings.ReplaceAll(raw, "\t", "  ")
+		trimmed := strings.TrimSpace(raw)
+		if strings.HasPrefix(trimmed, "```") {
+			inCode = !inCode
+			continue
+		}
+		if inCode {
+			lines = append(lines, codeStyle.Render("  "+raw))
+			continue
+		}
+		if trimmed == "" {
+			lines = append(lines, "")
+			continue
+		}
+
+		indent := strings.Repeat(" ", len(raw)-len(strings.TrimLeft(raw, " ")))
+		marker, body, style := markdownBlock(trimmed)
+		prefix := indent + marker
+		pw := lipgloss.Width(prefix)
+		wrapped := strings.Split(ansi.Wrap(renderInline(body, style), max(width-pw, 10), ""), "\n")
+		for i, l := range wrapped {
+			if i == 0 {
+				lines = append(lines, descStyle.Render(prefix)+l)
+			} else {
+				lines = append(lines, strings.Repeat(" ", pw)+l)
+			}
+		}
+	}
+	return lines
+}
+
+func markdownBlock(line string) (marker, body string, style lipgloss.Style) {
+	if level := len(line) - len(strings.TrimLeft(line, "#")); level > 0 && strings.HasPrefix(line[level:], " ") {
+		return "", strings.TrimSpace(line[level:]), headingStyle
+	}
+	for _, task := range []string{"- [ ] ", "* [ ] "} {
+		if strings.HasPrefix(line, task) {
+			return "☐ ", line[len(task):], normalStyle
+		}
+	}
+	for _, task := range []string{"- [x] ", "- [X] ", "* [x] ", "* [X] "} {
+		if strings.HasPrefix(line, task) {
+			return "☑ ", line[len(task):], completedStyle
+		}
+	}
+	if strings.HasPrefix(line, "- ") || strings.HasPrefix(line, "* ") || strings.HasPrefix(line, "+ ") {
+		return "• ", line[2:], normalStyle
+	}
+	if strings.HasPrefix(line, ">") {
+		return "│ ", strings.TrimSpace(line[1:]), descStyle
+	}
+	return "", line, normalStyle
+}
+
+func renderInline(s string, base lipgloss.Style) string {
+	var b, plain strings.Builder
+	flush := func() {
+		if plain.Len() > 0 {
+			b.WriteString(base.Render(plain.String()))
+			plain.Reset()
+		}
+	}
+	for len(s) > 0 {
+		if span, rest, style, ok := inlineSpan(s, base); ok {
+			flush()
+			b.WriteString(style.Render(span))
+			s = rest
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s)
+		plain.WriteRune(r)
+		s = s[size:]
+	}
+	flush()
+	return b.String()
+}
+
+// inlineSpan recognises an emphasised, code or link span at the start of
+// s and returns its text, the remainder and the style to draw it with.
+func inlineSpan(s string, base lipgloss.Style) (string, string, lipgloss.Style, bool) {
+	spans := []struct {
+		delim string
+		style lipgloss.Style
+	}{
+		{"`", codeStyle},
+		{"**", base.Bold(true)},
+		{"*", base.Italic(true)},
+	}
+	for _, sp := range spans {
+		if !strings.HasPrefix(s, sp.delim) {
+			continue
+		}
+		n := len(sp.delim)
+		end := strings.Index(s[n:], sp.delim)
+		if end <= 0 {
+			continue
+		}
+		return s[n : n+end], s[2*n+end:], sp.style, true
+	}
+	if strings.HasPrefix(s, "[") {
+		if mid := strings.Index(s, "]("); mid > 0 {
+			if end := strings.Index(s[mid:], ")"); end > 0 {
+				return s[1:mid], s[mid+end+1:], base.Underline(true), true
+			}
+		}
+	}
+	return "", "", base, false
+}
@@ -1,10 +1,13 @@
 package tui
 
 import (
+	"errors"
 	"fmt"
 	"os/exec"
+	"path/filepath"
 	"runtime"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/textarea"
 	tea "github.com/charmbracelet/bubbletea"
@@ -28,6 +31,11 @@ const (
 	modeNormal inputMode = iota
 	modeInput
 	modeConfirmDelete
+	modeConfirmRecover
+	modeConfirmConflict
+	modeHelp
+	modeSearch
+	modePicker
 )
 
 const (
@@ -35,17 +43,33 @@ const (
 	targetAddProject
 	targetEditProject
 	targetAddTodo
+	targetAddSubtask
 	targetEditTodo
 	targetSetLink
+	targetSetDue
+	targetSetTags
+	targetSetNotes
+	targetTagFilter
+	targetExportFile
+)
+
+const (
+	inputCharLimit = 120
+	inputHeight    = 3
 )
 
 type Model struct {
 	store         model.Store
 	focus         focusArea
+	view          todoView
+	tagFilter     string
+	search        string
 	mode          inputMode
 	target        inputTarget
 	projectCursor int
 	todoCursor    int
+	projectOffset int
+	todoOffset    int
 	width         int
 	height        int
 	status        string
@@ -53,6 +77,20 @@ type Model struct {
 	input         textarea.Model
 	dataPath      string
 	deleteMessage string
+	showDetail    bool
+	editorFile    editorFile
+	pickerRef     todoRef
+	pickerCopy    bool
+	exportDone    bool
+	pickerQuery   string
+	pickerCursor  int
+	readOnly      bool
+	dirty         bool
+	diskStamp     storage.Stamp
+	recoverPath   string
+	recoverStore  model.Store
+	undoStack     []snapshot
+	redoStack     []snapshot
 }
 
 func New(path string) Model {
@@ -64,9 +102,9 @@ func New(path string) Model {
 
 	ti := textarea.New()
 	ti.Prompt = ""
-	ti.CharLimit = 120
+	ti.CharLimit = inputCharLimit
 	ti.SetWidth(40)
-	ti.SetHeight(3)
+	ti.SetHeight(inputHeight)
 	ti.ShowLineNumbers = false
 
 	m := Model{
@@ -79,12 +117,22 @@ func New(path string) Model {
 		input:     ti,
 		dataPath:  path,
 	}
+	var corrupt *storage.CorruptError
+	if errors.As(err, &corrupt) {
+		if backup, bs, berr := storage.LatestBackup(path); berr == nil {
+			m.mode = modeConfirmRecover
+			m.recoverPath = backup
+			m.recoverStore = bs
+		}
+	}
+	m.readOnly = err != nil
+	m.diskStamp, _ = storage.StatFile(path)
 	m.clampCursors()
 	return m
 }
 
 func (m Model) Init() tea.Cmd {
-	return nil
+	return watchFile()
 }
 
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -92,25 +140,57 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
+		m.scrollIntoView()
 		return m, nil
 	case tea.KeyMsg:
-		if m.mode == modeInput {
-			return m.handleInputKeys(msg)
-		}
-		if m.mode == modeConfirmDelete {
-			return m.handleConfirmDeleteKeys(msg)
-		}
-		return m.handleNormalKeys(msg)
+		next, cmd := m.handleKey(msg)
+		nm := next.(Model)
+		nm.scrollIntoView()
+		return nm, cmd
+	case fileCheckMsg:
+		m.checkFile()
+		m.scrollIntoView()
+		return m, watchFile()
+	case editorDoneMsg:
+		m.finishExternalEdit(msg)
+		m.clampCursors()
+		m.scrollIntoView()
+		return m, nil
 	default:
 		return m, nil
 	}
 }
 
+func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch m.mode {
+	case modeInput:
+		return m.handleInputKeys(msg)
+	case modeConfirmDelete:
+		return m.handleConfirmDeleteKeys(msg)
+	case modeConfirmRecover:
+		return m.handleConfirmRecoverKeys(msg)
+	case modeConfirmConflict:
+		return m.handleConflictKeys(msg)
+	case modeSearch:
+		return m.handleSearchKeys(msg)
+	case modePicker:
+		return m.handlePickerKeys(msg)
+	case modeHelp:
+		m.mode = modeNormal
+		return m, nil
+	default:
+		return m.handleNormalKeys(msg)
+	}
+}
+
 func (m Model) handleInputKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
 	case "ctrl+c":
 		return m, tea.Quit
 	case "esc":
+		if m.target == targetSetNotes {
+			m.resetInputSize()
+		}
 		m.mode = modeNormal
 		m.target = targetNone
 		m.input.Blur()
@@ -123,8 +203,24 @@ func (m Model) handleInputKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.input, cmd = m.input.Update(enterMsg)
 		return m, cmd
 	case "enter":
+		if m.target == targetSetNotes {
+			var cmd tea.Cmd
+			m.input, cmd = m.input.Update(msg)
+			return m, cmd
+		}
+		m.commitInput()
+		return m, nil
+	case "ctrl+s":
 		m.commitInput()
 		return m, nil
+	case "tab":
+		if m.isTagTarget() {
+			m.completeTag()
+		}
+		if m.target == targetExportFile {
+			m.exportDone = !m.exportDone
+		}
+		return m, nil
 	default:
 		var cmd tea.Cmd
 		m.input, cmd = m.input.Update(msg)
@@ -132,10 +228,50 @@ func (m Model) handleInputKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	}
 }
 
+func (m Model) handleSearchKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return m, tea.Quit
+	case tea.KeyEsc:
+		m.mode = modeNormal
+		m.clearSearch()
+		return m, nil
+	case tea.KeyEnter:
+		m.mode = modeNormal
+		if m.search == "" {
+			m.status = "Search cleared"
+			m.statusErr = false
+			return m, nil
+		}
+		n := len(m.searchMatches())
+		m.status = fmt.Sprintf("%d matches for %q (n/N to jump, esc to clear)", n, m.search)
+		m.statusErr = n == 0
+		return m, nil
+	case tea.KeyBackspace:
+		r := []rune(m.search)
+		if len(r) > 0 {
+			m.search = string(r[:len(r)-1])
+		}
+	case tea.KeyCtrlU:
+		m.search = ""
+	case tea.KeySpace:
+		m.search += " "
+	case tea.KeyRunes:
+		m.search += string(msg.Runes)
+	default:
+		return m, nil
+	}
+	m.clampCursors()
+	return m, nil
+}
+
 func (m Model) handleNormalKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
 	case "ctrl+c", "q":
 		return m, tea.Quit
+	case "?":
+		m.mode = modeHelp
+		return m, nil
 	case "ctrl+h", "left":
 		m.focus = focusProjects
 		m.status = "Focus: projects"
@@ -153,6 +289,18 @@ func (m Model) handleNormalKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.moveCursor(-1)
 	case "down", "j":
 		m.moveCursor(1)
+	case "pgup":
+		m.page(-1)
+	case "pgdown":
+		m.page(1)
+	case "ctrl+u":
+		m.page(-0.5)
+	case "ctrl+d":
+		m.page(0.5)
+	case "g", "home":
+		m.moveCursor(-len(m.store.Projects) - len(m.visibleTodos()))
+	case "G", "end":
+		m.moveCursor(len(m.store.Projects) + len(m.visibleTodos()))
 	case "enter":
 		m.handleEnter()
 	case "a":
@@ -160,20 +308,35 @@ func (m Model) handleNormalKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		if m.mode == modeInput {
 			return m, textarea.Blink
 		}
+	case "A":
+		if m.focus == focusTodos {
+			m.beginAddSubtask()
+			if m.mode == modeInput {
+				return m, textarea.Blink
+			}
+		}
 	case "e":
 		m.beginEdit()
 		if m.mode == modeInput {
 			return m, textarea.Blink
 		}
+	case "E":
+		return m, m.beginExternalEdit()
+	case "y":
+		m.copyMarkdown()
+	case "Y":
+		m.beginExportFile()
+		if m.mode == modeInput {
+			return m, textarea.Blink
+		}
 	case "d":
 		m.deleteCurrent()
 	case "l":
 		if m.focus == focusTodos {
-			p := m.currentProject()
-			if p != nil && len(p.Todos) > 0 {
+			if t := m.selectedTodo(); t != nil {
 				m.mode = modeInput
 				m.target = targetSetLink
-				m.input.SetValue(p.Todos[m.todoCursor].Link)
+				m.input.SetValue(t.Link)
 				m.input.Placeholder = "https://github.com/..."
 				m.input.Focus()
 				m.status = "Set link"
@@ -183,19 +346,105 @@ func (m Model) handleNormalKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.status = "No todo to set link on"
 			m.statusErr = true
 		}
+	case "i":
+		if m.focus == focusTodos {
+			m.beginNotes()
+			if m.mode == modeInput {
+				return m, textarea.Blink
+			}
+		}
+	case "v":
+		m.toggleDetail()
 	case "o":
 		if m.focus == focusTodos {
-			p := m.currentProject()
-			if p != nil && len(p.Todos) > 0 {
-				link := p.Todos[m.todoCursor].Link
-				if link != "" {
-					return m, openURL(link)
+			if t := m.selectedTodo(); t != nil {
+				if t.Link != "" {
+					return m, openURL(t.Link)
 				}
 				m.status = "No link set (use l to add one)"
 				m.statusErr = true
 				return m, nil
 			}
 		}
+	case "t":
+		if m.focus == focusTodos {
+			if t := m.selectedTodo(); t != nil {
+				m.mode = modeInput
+				m.target = targetSetDue
+				m.input.SetValue(t.Due)
+				m.input.Placeholder = "2025-06-30, tomorrow, +3d, fri"
+				m.input.Focus()
+				m.status = "Set due date"
+				m.statusErr = false
+				return m, textarea.Blink
+			}
+			m.status = "No todo to set a due date on"
+			m.statusErr = true
+		}
+	case "X":
+		if m.focus == focusTodos {
+			m.completeTree()
+		}
+	case "z":
+		if m.focus == focusTodos {
+			m.toggleCollapse()
+		}
+	case "tab", "shift+tab":
+		if m.focus == focusTodos {
+			m.indentSelected(msg.String() == "shift+tab")
+		}
+	case "D":
+		m.toggleDueSoon()
+	case "W":
+		m.toggleDoneThisWeek()
+	case "p", "P":
+		if m.focus == focusTodos {
+			m.setPriority(msg.String() == "p")
+		}
+	case "s":
+		m.cycleSort()
+	case "m", "c":
+		if m.focus == focusTodos {
+			m.beginPicker(msg.String() == "c")
+		}
+	case "K", "alt+up":
+		m.moveSelected(-1)
+	case "J", "alt+down":
+		m.moveSelected(1)
+	case "T":
+		m.moveSelected(moveTop)
+	case "B":
+		m.moveSelected(moveBottom)
+	case "u":
+		m.undo()
+	case "ctrl+r":
+		m.redo()
+	case "#":
+		if m.focus == focusTodos {
+			return m, m.beginTags()
+		}
+	case "f":
+		return m, m.beginTagFilter()
+	case "/":
+		m.mode = modeSearch
+		m.search = ""
+		m.status = ""
+		m.statusErr = false
+	case "n", "N":
+		if msg.String() == "n" {
+			m.jumpMatch(1)
+		} else {
+			m.jumpMatch(-1)
+		}
+	case "esc":
+		if m.search != "" {
+			m.clearSearch()
+		} else if m.view != viewProject {
+			m.view = viewProject
+			m.todoCursor = 0
+			m.status = "Showing project todos"
+			m.statusErr = false
+		}
 	}
 
 	m.clampCursors()
@@ -214,6 +463,11 @@ func (m *Model) beginAdd() {
 		return
 	}
 
+	if m.view != viewProject {
+		m.status = "Open a project to add todos"
+		m.statusErr = true
+		return
+	}
 	if m.currentProject() == nil {
 		m.status = "Create a project first"
 		m.statusErr = true
@@ -245,15 +499,15 @@ func (m *Model) beginEdit() {
 		return
 	}
 
-	p := m.currentProject()
-	if p == nil || len(p.Todos) == 0 {
+	t := m.selectedTodo()
+	if t == nil {
 		m.status = "No todo to edit"
 		m.statusErr = true
 		return
 	}
 	m.mode = modeInput
 	m.target = targetEditTodo
-	m.input.SetValue(p.Todos[m.todoCursor].Title)
+	m.input.SetValue(t.Title)
 	m.input.Placeholder = "Todo title"
 	m.input.Focus()
 	m.status = "Edit todo"
@@ -261,12 +515,27 @@ func (m *Model) beginEdit() {
 }
 
 func (m *Model) commitInput() {
+	if m.target == targetSetNotes {
+		m.commitNotes(m.input.Value())
+		return
+	}
+
 	value := strings.TrimSpace(strings.ReplaceAll(m.input.Value(), "\n", " "))
 
+	if m.target == targetExportFile {
+		if m.commitExportFile(value) {
+			m.mode = modeNormal
+			m.target = targetNone
+			m.input.Blur()
+		}
+		return
+	}
+
 	if m.target == targetSetLink {
-		p := m.currentProject()
-		if p != nil && len(p.Todos) > 0 {
-			p.Todos[m.todoCursor].Link = value
+		if t := m.selectedTodo(); t != nil {
+			m.checkpoint(fmt.Sprintf("set link on %q", t.Title))
+			t.Link = value
+			t.Touch(time.Now())
 			if value == "" {
 				m.status = "Link cleared"
 			} else {
@@ -282,6 +551,50 @@ func (m *Model) commitInput() {
 		return
 	}
 
+	if m.target == targetSetDue {
+		due, err := model.ParseDue(value, time.Now())
+		if err != nil {
+			m.status = err.Error()
+			m.statusErr = true
+			return
+		}
+		if ref, ok := m.selectedRef(); ok {
+			m.checkpoint(fmt.Sprintf("set due date on %q", m.todoAt(ref).Title))
+			m.todoAt(ref).Due = due
+			m.todoAt(ref).Touch(time.Now())
+			if due == "" {
+				m.status = "Due date cleared"
+			} else {
+				m.status = "Due " + due
+			}
+			m.statusErr = false
+			m.selectTodo(ref)
+		}
+		m.mode = modeNormal
+		m.target = targetNone
+		m.input.Blur()
+		m.clampCursors()
+		m.persist()
+		return
+	}
+
+	if m.isTagTarget() {
+		target := m.target
+		if target == targetSetTags {
+			m.commitTags(value)
+		} else {
+			m.commitTagFilter(value)
+		}
+		m.mode = modeNormal
+		m.target = targetNone
+		m.input.Blur()
+		m.clampCursors()
+		if target == targetSetTags {
+			m.persist()
+		}
+		return
+	}
+
 	if value == "" {
 		m.mode = modeNormal
 		m.target = targetNone
@@ -293,13 +606,15 @@ func (m *Model) commitInput() {
 
 	switch m.target {
 	case targetAddProject:
-		m.store.Projects = append(m.store.Projects, model.Project{Name: value, Todos: []model.Todo{}})
+		m.checkpoint(fmt.Sprintf("add project %q", value))
+		m.store.Projects = append(m.store.Projects, model.Project{ID: model.NewID(), Name: value, Todos: []model.Todo{}})
 		m.projectCursor = len(m.store.Projects) - 1
 		m.todoCursor = 0
 		m.status = "Project created"
 		m.statusErr = false
 	case targetEditProject:
 		if len(m.store.Projects) > 0 {
+			m.checkpoint(fmt.Sprintf("rename project %q", m.store.Projects[m.projectCursor].Name))
 			m.store.Projects[m.projectCursor].Name = value
 			m.status = "Project updated"
 			m.statusErr = false
@@ -307,15 +622,20 @@ func (m *Model) commitInput() {
 	case targetAddTodo:
 		p := m.currentProject()
 		if p != nil {
-			p.Todos = append(p.Todos, model.Todo{Title: value})
-			m.todoCursor = len(p.Todos) - 1
+			m.checkpoint(fmt.Sprintf("add todo %q", value))
+			t := model.NewTodo(value, time.Now())
+			p.Todos = append(p.Todos, t)
+			m.selectTodo(refTo(*p, t))
 			m.status = "Todo created"
 			m.statusErr = false
 		}
+	case targetAddSubtask:
+		m.addSubtask(value)
 	case targetEditTodo:
-		p := m.currentProject()
-		if p != nil && len(p.Todos) > 0 {
-			p.Todos[m.todoCursor].Title = value
+		if t := m.selectedTodo(); t != nil {
+			m.checkpoint(fmt.Sprintf("edit todo %q", t.Title))
+			t.Title = value
+			t.Touch(time.Now())
 			m.status = "Todo updated"
 			m.statusErr = false
 		}
@@ -336,20 +656,27 @@ func (m *Model) handleEnter() {
 			return
 		}
 		m.focus = focusTodos
+		m.view = viewProject
+		m.todoCursor = 0
 		m.clampCursors()
 		m.status = fmt.Sprintf("Opened %q", m.store.Projects[m.projectCursor].Name)
 		m.statusErr = false
 		return
 	}
 
-	p := m.currentProject()
-	if p == nil || len(p.Todos) == 0 {
+	t := m.selectedTodo()
+	if t == nil {
 		m.status = "No todo selected"
 		m.statusErr = true
 		return
 	}
-	p.Todos[m.todoCursor].Completed = !p.Todos[m.todoCursor].Completed
-	if p.Todos[m.todoCursor].Completed {
+	if t.Completed {
+		m.checkpoint(fmt.Sprintf("reopen %q", t.Title))
+	} else {
+		m.checkpoint(fmt.Sprintf("complete %q", t.Title))
+	}
+	t.SetCompleted(!t.Completed, time.Now())
+	if t.Completed {
 		m.status = "Todo completed"
 	} else {
 		m.status = "Todo reopened"
@@ -373,15 +700,18 @@ func (m *Model) deleteCurrent() {
 		return
 	}
 
-	p := m.currentProject()
-	if p == nil || len(p.Todos) == 0 {
+	t := m.selectedTodo()
+	if t == nil {
 		m.status = "No todo to delete"
 		m.statusErr = true
 		return
 	}
-	title := p.Todos[m.todoCursor].Title
+	title := t.Title
 	m.mode = modeConfirmDelete
 	m.deleteMessage = fmt.Sprintf("Delete todo %q? (y/n)", title)
+	if n := model.CountTodos(t.Subtasks); n > 0 {
+		m.deleteMessage = fmt.Sprintf("Delete todo %q and %d subtasks? (y/n)", title, n)
+	}
 	m.status = m.deleteMessage
 	m.statusErr = false
 }
@@ -391,8 +721,10 @@ func (m *Model) confirmDelete() {
 		if len(m.store.Projects) == 0 {
 			return
 		}
-		name := m.store.Projects[m.projectCursor].Name
-		m.store.Projects = append(m.store.Projects[:m.projectCursor], m.store.Projects[m.projectCursor+1:]...)
+		p := m.store.Projects[m.projectCursor]
+		name := p.Name
+		m.checkpoint(fmt.Sprintf("delete project %q", name))
+		m.store.RemoveProject(p.ID)
 		m.clampCursors()
 		m.focus = focusProjects
 		m.status = fmt.Sprintf("Deleted project %q", name)
@@ -401,12 +733,13 @@ func (m *Model) confirmDelete() {
 		return
 	}
 
-	p := m.currentProject()
-	if p == nil || len(p.Todos) == 0 {
+	ref, ok := m.selectedRef()
+	if !ok {
 		return
 	}
-	title := p.Todos[m.todoCursor].Title
-	p.Todos = append(p.Todos[:m.todoCursor], p.Todos[m.todoCursor+1:]...)
+	title := m.todoAt(ref).Title
+	m.checkpoint(fmt.Sprintf("delete todo %q", title))
+	m.store.RemoveTodo(ref.todo)
 	m.clampCursors()
 	m.status = fmt.Sprintf("Deleted todo %q", title)
 	m.statusErr = false
@@ -418,9 +751,9 @@ func (m Model) handleConfirmDeleteKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case "ctrl+c":
 		return m, tea.Quit
 	case "y", "enter":
-		m.confirmDelete()
 		m.mode = modeNormal
 		m.deleteMessage = ""
+		m.confirmDelete()
 		return m, nil
 	case "n", "esc":
 		m.mode = modeNormal
@@ -432,24 +765,98 @@ func (m Model) handleConfirmDeleteKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+func (m Model) handleConfirmRecoverKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "y", "enter":
+		m.mode = modeNormal
+		m.store = m.recoverStore
+		m.recoverStore = model.Store{}
+		m.readOnly = false
+		m.clampCursors()
+		m.status = "Restored " + filepath.Base(m.recoverPath)
+		m.statusErr = false
+		m.persist()
+		return m, nil
+	case "n", "esc":
+		m.mode = modeNormal
+		m.recoverStore = model.Store{}
+		m.status = fmt.Sprintf("%s not loaded; changes will not be saved until it is fixed", m.dataPath)
+		m.statusErr = true
+		return m, nil
+	}
+	return m, nil
+}
+
+func (m Model) recoverMessage() string {
+	when := filepath.Base(m.recoverPath)
+	if t, ok := storage.BackupTime(m.recoverPath); ok {
+		when = t.Format("Jan 2 15:04")
+	}
+	return fmt.Sprintf("%s could not be read.\nRestore the backup from %s? (y/n)", filepath.Base(m.dataPath), when)
+}
+
 func (m *Model) moveCursor(delta int) {
 	if m.focus == focusProjects {
-		if len(m.store.Projects) == 0 {
+		visible := m.visibleProjects()
+		if len(visible) == 0 {
 			return
 		}
-		m.projectCursor += delta
+		pos := 0
+		for i, idx := range visible {
+			if idx == m.projectCursor {
+				pos = i
+				break
+			}
+		}
+		pos += delta
+		if pos < 0 {
+			pos = 0
+		}
+		if pos >= len(visible) {
+			pos = len(visible) - 1
+		}
+		m.projectCursor = visible[pos]
 		m.clampCursors()
 		return
 	}
 
-	p := m.currentProject()
-	if p == nil || len(p.Todos) == 0 {
+	if len(m.visibleTodos()) == 0 {
 		return
 	}
 	m.todoCursor += delta
 	m.clampCursors()
 }
 
+func (m *Model) toggleDoneThisWeek() {
+	if m.view == viewDoneThisWeek {
+		m.view = viewProject
+		m.status = "Showing project todos"
+	} else {
+		m.view = viewDoneThisWeek
+		m.focus = focusTodos
+		m.status = "Showing todos completed since Monday"
+	}
+	m.statusErr = false
+	m.todoCursor = 0
+	m.clampCursors()
+}
+
+func (m *Model) toggleDueSoon() {
+	if m.view == viewDueSoon {
+		m.view = viewProject
+		m.status = "Showing project todos"
+	} else {
+		m.view = viewDueSoon
+		m.focus = focusTodos
+		m.status = fmt.Sprintf("Showing todos due within %d days", dueSoonDays)
+	}
+	m.statusErr = false
+	m.todoCursor = 0
+	m.clampCursors()
+}
+
 func (m *Model) currentProject() *model.Project {
 	if len(m.store.Projects) == 0 {
 		return nil
@@ -473,9 +880,14 @@ func (m *Model) clampCursors() {
 	if m.projectCursor >= len(m.store.Projects) {
 		m.projectCursor = len(m.store.Projects) - 1
 	}
+	if m.search != "" && !m.projectMatches(m.store.Projects[m.projectCursor]) {
+		if visible := m.visibleProjects(); len(visible) > 0 {
+			m.projectCursor = visible[0]
+		}
+	}
 
-	p := &m.store.Projects[m.projectCursor]
-	if len(p.Todos) == 0 {
+	n := len(m.visibleTodos())
+	if n == 0 {
 		m.todoCursor = 0
 		return
 	}
@@ -483,8 +895,8 @@ func (m *Model) clampCursors() {
 	if m.todoCursor < 0 {
 		m.todoCursor = 0
 	}
-	if m.todoCursor >= len(p.Todos) {
-		m.todoCursor = len(p.Todos) - 1
+	if m.todoCursor >= n {
+		m.todoCursor = n - 1
 	}
 }
 
@@ -496,10 +908,22 @@ func (m Model) inputTitle() string {
 		return "Edit project"
 	case targetAddTodo:
 		return "New todo"
+	case targetAddSubtask:
+		return "New subtask"
 	case targetEditTodo:
 		return "Edit todo"
 	case targetSetLink:
 		return "Set link"
+	case targetSetDue:
+		return "Due date"
+	case targetSetTags:
+		return "Tags"
+	case targetSetNotes:
+		return "Notes"
+	case targetTagFilter:
+		return "Filter by tag"
+	case targetExportFile:
+		return "Export markdown"
 	default:
 		return "Input"
 	}
@@ -522,8 +946,23 @@ func openURL(url string) tea.Cmd {
 }
 
 func (m *Model) persist() {
+	if m.readOnly {
+		m.dirty = true
+		m.status = fmt.Sprintf("not saved: %s failed to load", m.dataPath)
+		m.statusErr = true
+		return
+	}
+	if stamp, err := storage.StatFile(m.dataPath); err == nil && !stamp.Equal(m.diskStamp) && stamp.Exists() {
+		m.dirty = true
+		m.mode = modeConfirmConflict
+		return
+	}
 	if err := storage.Save(m.dataPath, m.store); err != nil {
+		m.dirty = true
 		m.status = fmt.Sprintf("save failed: %v", err)
 		m.statusErr = true
+		return
 	}
+	m.dirty = false
+	m.diskStamp, _ = storage.StatFile(m.dataPath)
 }
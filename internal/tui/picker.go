@@ -0,0 +1,146 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const pickerRows = 10
+
+func (m *Model) beginPicker(copyTodo bool) {
+	ref, ok := m.selectedRef()
+	if !ok {
+		m.status = "No todo selected"
+		m.statusErr = true
+		return
+	}
+	m.pickerRef = ref
+	m.pickerCopy = copyTodo
+	m.pickerQuery = ""
+	m.pickerCursor = 0
+	if len(m.pickerProjects()) == 0 {
+		m.status = "No other project to move to"
+		m.statusErr = true
+		return
+	}
+	m.mode = modePicker
+	m.status = m.pickerTitle()
+	m.statusErr = false
+}
+
+// pickerProjects returns store indices of the projects offered as
+// destinations, narrowed by the fuzzy query.
+func (m Model) pickerProjects() []int {
+	var idx []int
+	for i, p := range m.store.Projects {
+		if !m.pickerCopy && p.ID == m.pickerRef.project {
+			continue
+		}
+		if _, ok := fuzzyMatch(m.pickerQuery, p.Name); ok {
+			idx = append(idx, i)
+		}
+	}
+	return idx
+}
+
+func (m Model) pickerTitle() string {
+	if m.pickerCopy {
+		return "Copy to project"
+	}
+	return "Move to project"
+}
+
+func (m Model) handlePickerKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc":
+		m.mode = modeNormal
+		m.status = "Cancelled"
+		m.statusErr = false
+		return m, nil
+	case "enter":
+		choices := m.pickerProjects()
+		if len(choices) == 0 {
+			return m, nil
+		}
+		m.mode = modeNormal
+		m.transferTodo(choices[m.pickerCursor])
+		return m, nil
+	case "up", "ctrl+p", "ctrl+k":
+		m.pickerCursor--
+	case "down", "ctrl+n", "ctrl+j":
+		m.pickerCursor++
+	case "backspace":
+		r := []rune(m.pickerQuery)
+		if len(r) > 0 {
+			m.pickerQuery = string(r[:len(r)-1])
+		}
+	case "ctrl+u":
+		m.pickerQuery = ""
+	default:
+		if msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace {
+			m.pickerQuery += string(msg.Runes)
+			m.pickerCursor = 0
+		}
+	}
+	m.pickerCursor = clampIndex(m.pickerCursor, len(m.pickerProjects()))
+	return m, nil
+}
+
+func (m *Model) transferTodo(dest int) {
+	src := m.todoAt(m.pickerRef)
+	if src == nil {
+		return
+	}
+	todo := src.Clone()
+	name := m.store.Projects[dest].Name
+
+	if m.pickerCopy {
+		m.checkpoint(fmt.Sprintf("copy %q to %q", todo.Title, name))
+		todo = todo.Copy()
+		m.store.Projects[dest].Todos = append(m.store.Projects[dest].Todos, todo)
+		m.status = fmt.Sprintf("Copied %q to %q", todo.Title, name)
+	} else {
+		m.checkpoint(fmt.Sprintf("move %q to %q", todo.Title, name))
+		m.store.RemoveTodo(todo.ID)
+		todo.Touch(time.Now())
+		m.store.Projects[dest].Todos = append(m.store.Projects[dest].Todos, todo)
+		m.status = fmt.Sprintf("Moved %q to %q", todo.Title, name)
+	}
+	m.statusErr = false
+	m.clampCursors()
+	m.persist()
+}
+
+func (m Model) pickerBody(width int) string {
+	var b strings.Builder
+	b.WriteString(inputLabelStyle.Render("> ") + normalStyle.Render(m.pickerQuery+"█"))
+	b.WriteString("\n")
+
+	choices := m.pickerProjects()
+	if len(choices) == 0 {
+		b.WriteString("\n" + descStyle.Render("No matching projects"))
+		return b.String()
+	}
+
+	offset := clampOffset(0, m.pickerCursor, len(choices), pickerRows)
+	end := offset + pickerRows
+	if end > len(choices) {
+		end = len(choices)
+	}
+	for i := offset; i < end; i++ {
+		name := m.store.Projects[choices[i]].Name
+		style, prefix := normalStyle, "  "
+		if i == m.pickerCursor {
+			style, prefix = selectedStyle, "▶ "
+		}
+		positions, _ := fuzzyMatch(m.pickerQuery, name)
+		line := style.Render(prefix) + highlight(name, positions, style)
+		b.WriteString("\n" + truncateLine(line, width))
+	}
+	return b.String()
+}
@@ -0,0 +1,102 @@
+package tui
+
+import (
+	"fmt"
+
+	"github.com/danjecu/focusboard-tui/internal/model"
+)
+
+const (
+	moveTop    = -1 << 30
+	moveBottom = 1 << 30
+)
+
+// moveSelected shifts the selected project or todo by delta positions,
+// clamped to the list bounds; moveTop/moveBottom jump to either end.
+func (m *Model) moveSelected(delta int) {
+	if m.search != "" {
+		m.status = "Clear the search before reordering"
+		m.statusErr = true
+		return
+	}
+
+	if m.focus == focusProjects {
+		if len(m.store.Projects) == 0 {
+			m.status = "No project to move"
+			m.statusErr = true
+			return
+		}
+		from := m.projectCursor
+		to := clampIndex(from+delta, len(m.store.Projects))
+		if from == to {
+			return
+		}
+		m.checkpoint(fmt.Sprintf("move project %q", m.store.Projects[from].Name))
+		moveElem(m.store.Projects, from, to)
+		m.projectCursor = to
+		m.status = fmt.Sprintf("Moved project to position %d", to+1)
+		m.statusErr = false
+		m.persist()
+		return
+	}
+
+	p := m.manualProject("reorder its todos")
+	if p == nil {
+		return
+	}
+	ref, ok := m.selectedRef()
+	if !ok {
+		m.status = "No todo to move"
+		m.statusErr = true
+		return
+	}
+	list, from, _ := p.Locate(ref.todo)
+	to := clampIndex(from+delta, len(*list))
+	if from == to {
+		return
+	}
+	m.checkpoint(fmt.Sprintf("move todo %q", (*list)[from].Title))
+	moveElem(*list, from, to)
+	m.selectTodo(ref)
+	m.status = fmt.Sprintf("Moved todo to position %d", to+1)
+	m.statusErr = false
+	m.persist()
+}
+
+// manualProject returns the open project when its todos can be
+// rearranged: the project view, manually sorted. Otherwise it explains why
+// not in the status line and returns nil.
+func (m *Model) manualProject(action string) *model.Project {
+	p := m.currentProject()
+	if m.view != viewProject || p == nil {
+		m.status = "Open a project to " + action
+		m.statusErr = true
+		return nil
+	}
+	if p.Sort != model.SortManual {
+		m.status = fmt.Sprintf("Sorted by %s; press s until manual to %s", p.Sort, action)
+		m.statusErr = true
+		return nil
+	}
+	return p
+}
+
+func moveElem[T any](s []T, from, to int) {
+	item := s[from]
+	if from < to {
+		copy(s[from:to], s[from+1:to+1])
+	} else {
+		copy(s[to+1:from+1], s[to:from])
+	}
+	s[to] = item
+}
+
+func clampIndex(i, n int) int {
+	if i < 0 {
+		return 0
+	}
+	if i >= n {
+		return n - 1
+	}
+	return i
+}
@@ -0,0 +1,74 @@
+package tui
+
+import "fmt"
+
+const bottomLines = 2
+
+func (m Model) paneHeight() int {
+	h := m.height - bottomLines - 2
+	if dh := m.detailHeight(); dh > 0 {
+		h -= dh + 2
+	}
+	if h < 1 {
+		h = 1
+	}
+	return h
+}
+
+func (m *Model) projectPos() int {
+	for i, idx := range m.visibleProjects() {
+		if idx == m.projectCursor {
+			return i
+		}
+	}
+	return 0
+}
+
+// scrollIntoView adjusts the pane offsets so both cursors stay on screen.
+func (m *Model) scrollIntoView() {
+	h := m.paneHeight()
+	m.projectOffset = clampOffset(m.projectOffset, m.projectPos(), len(m.visibleProjects()), h)
+	m.todoOffset = clampOffset(m.todoOffset, m.todoCursor, len(m.visibleTodos()), h)
+}
+
+func clampOffset(offset, cursor, total, height int) int {
+	if cursor < offset {
+		offset = cursor
+	}
+	if cursor >= offset+height {
+		offset = cursor - height + 1
+	}
+	if last := total - height; offset > last {
+		offset = last
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return offset
+}
+
+func (m *Model) page(fraction float64) {
+	step := int(float64(m.paneHeight()) * fraction)
+	if step == 0 {
+		if fraction < 0 {
+			step = -1
+		} else {
+			step = 1
+		}
+	}
+	m.moveCursor(step)
+}
+
+func scrollTitle(title string, cursor, total, offset, height int) string {
+	if total <= height {
+		return title
+	}
+	title += fmt.Sprintf(" · %d/%d", cursor+1, total)
+	if offset > 0 {
+		title += " ↑"
+	}
+	if offset+height < total {
+		title += " ↓"
+	}
+	return title
+}
@@ -0,0 +1,163 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/danjecu/focusboard-tui/internal/model"
+)
+
+var matchStyle = lipgloss.NewStyle().Underline(true).Bold(true)
+
+// fuzzyMatch reports whether every rune of query appears in text in order,
+// ignoring case, and returns the rune positions it matched.
+func fuzzyMatch(query, text string) ([]int, bool) {
+	q := []rune(strings.ToLower(query))
+	if len(q) == 0 {
+		return nil, true
+	}
+	var positions []int
+	qi := 0
+	for i, r := range []rune(text) {
+		if qi < len(q) && unicode.ToLower(r) == q[qi] {
+			positions = append(positions, i)
+			qi++
+		}
+	}
+	if qi < len(q) {
+		return nil, false
+	}
+	return positions, true
+}
+
+func (m Model) todoMatches(t model.Todo) bool {
+	if m.search == "" {
+		return true
+	}
+	if _, ok := fuzzyMatch(m.search, t.Title); ok {
+		return true
+	}
+	_, ok := fuzzyMatch(m.search, t.Link)
+	return ok && t.Link != ""
+}
+
+func (m Model) projectMatches(p model.Project) bool {
+	if m.search == "" {
+		return true
+	}
+	if _, ok := fuzzyMatch(m.search, p.Name); ok {
+		return true
+	}
+	found := false
+	model.Walk(p.Todos, func(t *model.Todo, _ int) {
+		found = found || m.todoMatches(*t)
+	})
+	return found
+}
+
+func (m Model) filterRefs(refs []todoRef) []todoRef {
+	if m.search == "" {
+		return refs
+	}
+	out := refs[:0]
+	for _, ref := range refs {
+		if t := m.todoAt(ref); t != nil && m.todoMatches(*t) {
+			out = append(out, ref)
+		}
+	}
+	return out
+}
+
+// searchMatches lists every matching todo across projects in display order.
+func (m *Model) searchMatches() []todoRef {
+	var refs []todoRef
+	for _, p := range m.store.Projects {
+		refs = append(refs, m.filterRefs(m.treeRefs(p))...)
+	}
+	return refs
+}
+
+func (m *Model) jumpMatch(delta int) {
+	if m.search == "" {
+		m.status = "No active search (press / to search)"
+		m.statusErr = true
+		return
+	}
+	matches := m.searchMatches()
+	if len(matches) == 0 {
+		m.status = fmt.Sprintf("No matches for %q", m.search)
+		m.statusErr = true
+		return
+	}
+
+	current := -1
+	if ref, ok := m.selectedRef(); ok && m.focus == focusTodos {
+		for i, r := range matches {
+			if r.todo == ref.todo {
+				current = i
+				break
+			}
+		}
+	}
+	next := 0
+	if current >= 0 {
+		next = (current + delta + len(matches)) % len(matches)
+	} else if delta < 0 {
+		next = len(matches) - 1
+	}
+
+	target := matches[next]
+	m.view = viewProject
+	m.focus = focusTodos
+	m.projectCursor = m.store.ProjectIndex(target.project)
+	m.todoCursor = 0
+	m.selectTodo(target)
+	m.status = fmt.Sprintf("Match %d/%d for %q", next+1, len(matches), m.search)
+	m.statusErr = false
+}
+
+func (m *Model) clearSearch() {
+	m.search = ""
+	m.clampCursors()
+	m.status = "Search cleared"
+	m.statusErr = false
+}
+
+func highlight(text string, positions []int, base lipgloss.Style) string {
+	if len(positions) == 0 {
+		return base.Render(text)
+	}
+	hl := base.Inherit(matchStyle)
+	var b strings.Builder
+	var run []rune
+	matched := false
+	flush := func() {
+		if len(run) == 0 {
+			return
+		}
+		if matched {
+			b.WriteString(hl.Render(string(run)))
+		} else {
+			b.WriteString(base.Render(string(run)))
+		}
+		run = run[:0]
+	}
+
+	pi := 0
+	for i, r := range []rune(text) {
+		isMatch := pi < len(positions) && positions[pi] == i
+		if isMatch {
+			pi++
+		}
+		if isMatch != matched {
+			flush()
+			matched = isMatch
+		}
+		run = append(run, r)
+	}
+	flush()
+	return b.String()
+}
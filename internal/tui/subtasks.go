@@ -0,0 +1,154 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/danjecu/focusboard-tui/internal/model"
+)
+
+func (m *Model) beginAddSubtask() {
+	if m.view != viewProject {
+		m.status = "Open a project to add subtasks"
+		m.statusErr = true
+		return
+	}
+	if m.selectedTodo() == nil {
+		m.status = "Select a todo to add a subtask to"
+		m.statusErr = true
+		return
+	}
+	m.mode = modeInput
+	m.target = targetAddSubtask
+	m.input.SetValue("")
+	m.input.Placeholder = "Subtask title"
+	m.input.Focus()
+	m.status = "Add subtask"
+	m.statusErr = false
+}
+
+func (m *Model) addSubtask(title string) {
+	ref, ok := m.selectedRef()
+	if !ok {
+		return
+	}
+	parent := m.todoAt(ref)
+	m.checkpoint(fmt.Sprintf("add subtask %q", title))
+	now := time.Now()
+	t := model.NewTodo(title, now)
+	parent.Subtasks = append(parent.Subtasks, t)
+	parent.Collapsed = false
+	parent.Touch(now)
+	m.selectTodo(todoRef{project: ref.project, todo: t.ID})
+	m.status = "Subtask created"
+	m.statusErr = false
+}
+
+// indentSelected nests the selected todo under its previous sibling or,
+// with outdent, lifts it one level up next to its parent.
+func (m *Model) indentSelected(outdent bool) {
+	if m.search != "" {
+		m.status = "Clear the search before changing nesting"
+		m.statusErr = true
+		return
+	}
+	p := m.manualProject("change nesting")
+	if p == nil {
+		return
+	}
+	ref, ok := m.selectedRef()
+	if !ok {
+		m.status = "No todo selected"
+		m.statusErr = true
+		return
+	}
+
+	clone := p.Clone()
+	var changed bool
+	if outdent {
+		changed = clone.Outdent(ref.todo)
+	} else {
+		changed = clone.Indent(ref.todo)
+	}
+	if !changed {
+		if outdent {
+			m.status = "Already at the top level"
+		} else {
+			m.status = "Nothing above to nest under"
+		}
+		m.statusErr = true
+		return
+	}
+
+	title := m.todoAt(ref).Title
+	if outdent {
+		m.checkpoint(fmt.Sprintf("outdent %q", title))
+		m.status = "Outdented todo"
+	} else {
+		m.checkpoint(fmt.Sprintf("indent %q", title))
+		m.status = "Indented todo"
+	}
+	*p = clone
+	m.selectTodo(ref)
+	m.statusErr = false
+	m.persist()
+}
+
+func (m *Model) toggleCollapse() {
+	t := m.selectedTodo()
+	if t == nil {
+		return
+	}
+	if len(t.Subtasks) == 0 {
+		m.status = "No subtasks to fold"
+		m.statusErr = true
+		return
+	}
+	ref, _ := m.selectedRef()
+	t.Collapsed = !t.Collapsed
+	if t.Collapsed {
+		m.status = fmt.Sprintf("Folded %q", t.Title)
+	} else {
+		m.status = fmt.Sprintf("Unfolded %q", t.Title)
+	}
+	m.statusErr = false
+	m.selectTodo(ref)
+	m.persist()
+}
+
+// completeTree toggles the selected todo and gives every subtask beneath
+// it the same state.
+func (m *Model) completeTree() {
+	t := m.selectedTodo()
+	if t == nil {
+		m.status = "No todo selected"
+		m.statusErr = true
+		return
+	}
+	done := !t.Completed
+	if done {
+		m.checkpoint(fmt.Sprintf("complete %q with subtasks", t.Title))
+	} else {
+		m.checkpoint(fmt.Sprintf("reopen %q with subtasks", t.Title))
+	}
+	t.SetCompletedTree(done, time.Now())
+	if done {
+		m.status = fmt.Sprintf("Completed %q and its subtasks", t.Title)
+	} else {
+		m.status = fmt.Sprintf("Reopened %q and its subtasks", t.Title)
+	}
+	m.statusErr = false
+	m.persist()
+}
+
+func progressLabel(t model.Todo) string {
+	done, total := t.Progress()
+	if total == 0 {
+		return ""
+	}
+	fold := "▾"
+	if t.Collapsed {
+		fold = "▸"
+	}
+	return fmt.Sprintf("%s %d/%d", fold, done, total)
+}
@@ -0,0 +1,155 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/danjecu/focusboard-tui/internal/model"
+)
+
+const maxTagSuggestions = 6
+
+func (m *Model) beginTags() tea.Cmd {
+	t := m.selectedTodo()
+	if t == nil {
+		m.status = "No todo to tag"
+		m.statusErr = true
+		return nil
+	}
+	m.mode = modeInput
+	m.target = targetSetTags
+	value := strings.Join(t.Tags, " ")
+	if value != "" {
+		value += " "
+	}
+	m.input.SetValue(value)
+	m.input.Placeholder = "review bug blocked (tab completes)"
+	m.input.Focus()
+	m.status = "Edit tags"
+	m.statusErr = false
+	return textarea.Blink
+}
+
+func (m *Model) beginTagFilter() tea.Cmd {
+	if len(m.store.Tags()) == 0 {
+		m.status = "No tags in use yet (use # on a todo)"
+		m.statusErr = true
+		return nil
+	}
+	m.mode = modeInput
+	m.target = targetTagFilter
+	m.input.SetValue(m.tagFilter)
+	m.input.Placeholder = "tag (tab completes, empty clears)"
+	m.input.Focus()
+	m.status = "Filter by tag"
+	m.statusErr = false
+	return textarea.Blink
+}
+
+func (m *Model) commitTags(value string) {
+	ref, ok := m.selectedRef()
+	if !ok {
+		return
+	}
+	tags := model.ParseTags(value)
+	m.checkpoint(fmt.Sprintf("set tags on %q", m.todoAt(ref).Title))
+	m.todoAt(ref).Tags = tags
+	m.todoAt(ref).Touch(time.Now())
+	m.selectTodo(ref)
+	if len(tags) == 0 {
+		m.status = "Tags cleared"
+	} else {
+		m.status = "Tags: " + formatTags(tags)
+	}
+	m.statusErr = false
+}
+
+func (m *Model) commitTagFilter(value string) {
+	tags := model.ParseTags(value)
+	if len(tags) == 0 {
+		m.tagFilter = ""
+		if m.view == viewTag {
+			m.view = viewProject
+		}
+		m.status = "Tag filter cleared"
+		m.statusErr = false
+		m.todoCursor = 0
+		return
+	}
+	m.tagFilter = tags[0]
+	m.view = viewTag
+	m.focus = focusTodos
+	m.todoCursor = 0
+	m.status = "Filtering by " + formatTags(tags[:1])
+	m.statusErr = false
+}
+
+func (m Model) isTagTarget() bool {
+	return m.target == targetSetTags || m.target == targetTagFilter
+}
+
+// tagSuggestions lists known tags that extend the word being typed.
+func (m Model) tagSuggestions() []string {
+	head, word := splitLastWord(m.input.Value())
+	word = strings.ToLower(strings.TrimLeft(word, "#"))
+	entered := map[string]bool{}
+	for _, tag := range model.ParseTags(head) {
+		entered[tag] = true
+	}
+	var out []string
+	for _, tag := range m.store.Tags() {
+		if strings.HasPrefix(tag, word) && tag != word && !entered[tag] {
+			out = append(out, tag)
+		}
+	}
+	return out
+}
+
+func (m *Model) completeTag() {
+	matches := m.tagSuggestions()
+	if len(matches) == 0 {
+		return
+	}
+	head, _ := splitLastWord(m.input.Value())
+	completion := matches[0]
+	if len(matches) > 1 {
+		completion = commonPrefix(matches)
+	} else if m.target == targetSetTags {
+		completion += " "
+	}
+	m.input.SetValue(head + completion)
+}
+
+func splitLastWord(s string) (string, string) {
+	i := strings.LastIndexAny(s, " ,\t\n")
+	return s[:i+1], s[i+1:]
+}
+
+// commonPrefix compares runes rather than bytes so that the completion
+// never ends inside a multi-byte character.
+func commonPrefix(words []string) string {
+	prefix := []rune(words[0])
+	for _, w := range words[1:] {
+		n := 0
+		for _, r := range w {
+			if n == len(prefix) || prefix[n] != r {
+				break
+			}
+			n++
+		}
+		prefix = prefix[:n]
+	}
+	return string(prefix)
+}
+
+func formatTags(tags []string) string {
+	out := make([]string, len(tags))
+	for i, t := range tags {
+		out[i] = "#" + t
+	}
+	return strings.Join(out, " ")
+}
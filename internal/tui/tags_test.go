@@ -0,0 +1,26 @@
+package tui
+
+import (
+	"testing"
+	"unicode/utf8"
+)
+
+func TestCommonPrefix(t *testing.T) {
+	for _, tc := range []struct {
+		words []string
+		want  string
+	}{
+		{[]string{"work"}, "work"},
+		{[]string{"work", "workshop"}, "work"},
+		{[]string{"review", "release"}, "re"},
+		{[]string{"abc", "xyz"}, ""},
+		// "é" and "è" share their first byte.
+		{[]string{"café", "cafè"}, "caf"},
+		{[]string{"日本", "日曜"}, "日"},
+	} {
+		got := commonPrefix(tc.words)
+		if got != tc.want || !utf8.ValidString(got) {
+			t.Errorf("commonPrefix(%q) = %q, want %q", tc.words, got, tc.want)
+		}
+	}
+}
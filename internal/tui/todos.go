@@ -0,0 +1,218 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/danjecu/focusboard-tui/internal/model"
+)
+
+type todoView int
+
+const (
+	viewProject todoView = iota
+	viewDueSoon
+	viewTag
+	viewDoneThisWeek
+)
+
+const dueSoonDays = 7
+
+// todoRef identifies a todo by the IDs of it and its project. The todo
+// pane lists refs so that views spanning several projects can share the
+// cursor and key handling; positions only exist on screen. depth is the
+// indentation the row is drawn with.
+type todoRef struct {
+	project string
+	todo    string
+	depth   int
+}
+
+func (m *Model) visibleTodos() []todoRef {
+	switch m.view {
+	case viewDueSoon:
+		return m.filterRefs(m.dueSoonTodos())
+	case viewTag:
+		return m.filterRefs(m.taggedTodos(m.tagFilter))
+	case viewDoneThisWeek:
+		return m.filterRefs(m.completedSince(model.StartOfWeek(time.Now())))
+	}
+
+	p := m.currentProject()
+	if p == nil || !m.projectMatches(*p) {
+		return nil
+	}
+	return m.filterRefs(m.treeRefs(*p))
+}
+
+// treeRefs flattens the project's todos in display order, descending into
+// subtasks unless their parent is collapsed. A search looks inside
+// collapsed todos too.
+func (m *Model) treeRefs(p model.Project) []todoRef {
+	var refs []todoRef
+	var add func(todos []model.Todo, depth int)
+	add = func(todos []model.Todo, depth int) {
+		for _, i := range model.SortedIndices(todos, p.Sort) {
+			t := todos[i]
+			refs = append(refs, todoRef{project: p.ID, todo: t.ID, depth: depth})
+			if !t.Collapsed || m.search != "" {
+				add(t.Subtasks, depth+1)
+			}
+		}
+	}
+	add(p.Todos, 0)
+	return refs
+}
+
+// visibleProjects returns the store indices of projects shown in the left
+// pane; projectCursor always holds a store index.
+func (m *Model) visibleProjects() []int {
+	idx := make([]int, 0, len(m.store.Projects))
+	for i, p := range m.store.Projects {
+		if m.projectMatches(p) {
+			idx = append(idx, i)
+		}
+	}
+	return idx
+}
+
+func (m *Model) dueSoonTodos() []todoRef {
+	now := time.Now()
+	var refs []todoRef
+	for _, p := range m.store.Projects {
+		model.Walk(p.Todos, func(t *model.Todo, _ int) {
+			if t.Completed {
+				return
+			}
+			if days, ok := t.DaysUntilDue(now); ok && days <= dueSoonDays {
+				refs = append(refs, refTo(p, *t))
+			}
+		})
+	}
+	sort.SliceStable(refs, func(i, j int) bool {
+		return m.todoAt(refs[i]).Due < m.todoAt(refs[j]).Due
+	})
+	return refs
+}
+
+func (m *Model) completedSince(since time.Time) []todoRef {
+	var refs []todoRef
+	for _, p := range m.store.Projects {
+		model.Walk(p.Todos, func(t *model.Todo, _ int) {
+			if t.Completed && !t.CompletedAt.Before(since) {
+				refs = append(refs, refTo(p, *t))
+			}
+		})
+	}
+	sort.SliceStable(refs, func(i, j int) bool {
+		return m.todoAt(refs[i]).CompletedAt.After(m.todoAt(refs[j]).CompletedAt)
+	})
+	return refs
+}
+
+func (m *Model) taggedTodos(tag string) []todoRef {
+	var refs []todoRef
+	for _, p := range m.store.Projects {
+		model.Walk(p.Todos, func(t *model.Todo, _ int) {
+			if t.HasTag(tag) {
+				refs = append(refs, refTo(p, *t))
+			}
+		})
+	}
+	return refs
+}
+
+func (m *Model) todoAt(ref todoRef) *model.Todo {
+	p := m.store.Project(ref.project)
+	if p == nil {
+		return nil
+	}
+	return p.Todo(ref.todo)
+}
+
+func (m *Model) selectedRef() (todoRef, bool) {
+	refs := m.visibleTodos()
+	if m.todoCursor < 0 || m.todoCursor >= len(refs) {
+		return todoRef{}, false
+	}
+	return refs[m.todoCursor], true
+}
+
+func (m *Model) selectedTodo() *model.Todo {
+	ref, ok := m.selectedRef()
+	if !ok {
+		return nil
+	}
+	return m.todoAt(ref)
+}
+
+func refTo(p model.Project, t model.Todo) todoRef {
+	return todoRef{project: p.ID, todo: t.ID}
+}
+
+func (m *Model) selectTodo(ref todoRef) {
+	for i, r := range m.visibleTodos() {
+		if r.todo == ref.todo {
+			m.todoCursor = i
+			return
+		}
+	}
+}
+
+func (m Model) todoPaneTitle() string {
+	switch m.view {
+	case viewDueSoon:
+		return "Due soon"
+	case viewTag:
+		return "Tagged #" + m.tagFilter
+	case viewDoneThisWeek:
+		return "Completed this week"
+	}
+	if p := m.currentProject(); p != nil {
+		if p.Sort != model.SortManual {
+			return fmt.Sprintf("Todos: %s (by %s)", p.Name, p.Sort)
+		}
+		return "Todos: " + p.Name
+	}
+	return "Todos"
+}
+
+func (m *Model) setPriority(raise bool) {
+	ref, ok := m.selectedRef()
+	if !ok {
+		m.status = "No todo to prioritise"
+		m.statusErr = true
+		return
+	}
+	t := m.todoAt(ref)
+	m.checkpoint(fmt.Sprintf("change priority of %q", t.Title))
+	if raise {
+		t.Priority = t.Priority.Raise()
+	} else {
+		t.Priority = t.Priority.Lower()
+	}
+	t.Touch(time.Now())
+	m.selectTodo(ref)
+	m.status = "Priority: " + t.Priority.String()
+	m.statusErr = false
+	m.persist()
+}
+
+func (m *Model) cycleSort() {
+	p := m.currentProject()
+	if m.view != viewProject || p == nil {
+		m.status = "Open a project to change its sort order"
+		m.statusErr = true
+		return
+	}
+	ref, hadSelection := m.selectedRef()
+	m.checkpoint(fmt.Sprintf("change sort of %q", p.Name))
+	p.Sort = p.Sort.Next()
+	if hadSelection {
+		m.selectTodo(ref)
+	}
+	m.status = "Sort: " + p.Sort.String()
+	m.statusErr = false
+	m.persist()
+}
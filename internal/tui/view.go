@@ -3,8 +3,12 @@ package tui
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/danjecu/focusboard-tui/internal/model"
 )
 
 var (
@@ -38,6 +42,30 @@ var (
 			Foreground(lipgloss.AdaptiveColor{Light: "25", Dark: "212"}).
 			Bold(true)
 
+	overdueStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.AdaptiveColor{Light: "160", Dark: "196"}).
+			Bold(true)
+
+	dueTodayStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.AdaptiveColor{Light: "166", Dark: "214"}).
+			Bold(true)
+
+	upcomingStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.AdaptiveColor{Light: "136", Dark: "179"})
+
+	highPriorityStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.AdaptiveColor{Light: "160", Dark: "203"}).
+				Bold(true)
+
+	mediumPriorityStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.AdaptiveColor{Light: "166", Dark: "214"})
+
+	lowPriorityStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.AdaptiveColor{Light: "30", Dark: "73"})
+
+	tagStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.AdaptiveColor{Light: "30", Dark: "80"})
+
 	focusedBorderColor = lipgloss.AdaptiveColor{Light: "25", Dark: "212"}
 	dimBorderColor     = lipgloss.AdaptiveColor{Light: "243", Dark: "241"}
 )
@@ -101,29 +129,26 @@ func (m Model) View() string {
 		return "Loading..."
 	}
 
-	bottomLines := 2
-	panelH := m.height - bottomLines - 2
-	if panelH < 1 {
-		panelH = 1
-	}
+	panelH := m.paneHeight()
 
 	leftTotal := m.width / 3
 	rightTotal := m.width - leftTotal
 
-	projectTitle := "Projects"
+	projectTitle := scrollTitle("Projects", m.projectPos(), len(m.visibleProjects()), m.projectOffset, panelH)
 	leftFocused := m.focus == focusProjects && m.mode == modeNormal
-	leftContent := m.padContent(m.projectLines(), panelH)
+	leftContent := m.padContent(m.projectLines(), m.projectOffset, panelH, leftTotal-4)
 	leftPane := renderPane(leftTotal, panelH, projectTitle, leftFocused, leftContent)
 
-	todoTitle := "Todos"
-	if p := m.currentProject(); p != nil {
-		todoTitle = fmt.Sprintf("Todos: %s", p.Name)
-	}
+	todoTitle := scrollTitle(m.todoPaneTitle(), m.todoCursor, len(m.visibleTodos()), m.todoOffset, panelH)
 	rightFocused := m.focus == focusTodos && m.mode == modeNormal
-	rightContent := m.padContent(m.todoLines(), panelH)
+	rightContent := m.padContent(m.todoLines(), m.todoOffset, panelH, rightTotal-4)
 	rightPane := renderPane(rightTotal, panelH, todoTitle, rightFocused, rightContent)
 
 	panels := lipgloss.JoinHorizontal(lipgloss.Top, leftPane, rightPane)
+	if dh := m.detailHeight(); dh > 0 {
+		detailContent := m.padContent(m.detailLines(m.width-4), 0, dh, m.width-4)
+		panels = lipgloss.JoinVertical(lipgloss.Left, panels, renderPane(m.width, dh, "Details", false, detailContent))
+	}
 
 	var b strings.Builder
 	b.WriteString(panels)
@@ -136,14 +161,15 @@ func (m Model) View() string {
 		helpKey("a", "add"),
 		helpKey("e", "edit"),
 		helpKey("d", "del"),
-		helpKey("l", "link"),
-		helpKey("o", "open"),
+		helpKey("?", "more"),
 		helpKey("q", "quit"),
 	}, "  ")
 	b.WriteString(help)
 	b.WriteString("\n")
 
-	if m.statusErr {
+	if m.mode == modeSearch {
+		b.WriteString(inputLabelStyle.Render("/") + statusStyle.Render(m.search+"█"))
+	} else if m.statusErr {
 		b.WriteString(errorStyle.Render("ERROR: " + m.status))
 	} else {
 		b.WriteString(statusStyle.Render(m.status))
@@ -151,20 +177,54 @@ func (m Model) View() string {
 
 	baseView := b.String()
 
+	if m.mode == modeHelp {
+		popup := renderPopup(m.helpWidth(), "Keys", m.helpBody())
+		return overlayCenter(baseView, popup, m.width, m.height)
+	}
+
+	popupWidth := m.width / 3
+	if popupWidth < 40 {
+		popupWidth = 40
+	}
+	if popupWidth > m.width-4 {
+		popupWidth = m.width - 4
+	}
+
+	if m.mode == modePicker {
+		popup := renderPopup(popupWidth, m.pickerTitle(), m.pickerBody(popupWidth-4))
+		return overlayCenter(baseView, popup, m.width, m.height)
+	}
+
+	if m.mode == modeConfirmConflict {
+		popup := renderPopup(min(popupWidth+16, m.width-4), "Conflict", m.conflictMessage())
+		return overlayCenter(baseView, popup, m.width, m.height)
+	}
+
+	if m.mode == modeConfirmRecover {
+		popup := renderPopup(popupWidth, "Recover", m.recoverMessage())
+		return overlayCenter(baseView, popup, m.width, m.height)
+	}
+
+	if m.mode == modeInput && m.target == targetSetNotes {
+		popupWidth = max(popupWidth, min(m.width*2/3, m.width-4))
+	}
+
 	if m.mode == modeInput || m.mode == modeConfirmDelete {
-		popupWidth := m.width / 3
-		if popupWidth < 40 {
-			popupWidth = 40
-		}
-		if popupWidth > m.width-4 {
-			popupWidth = m.width - 4
-		}
 
 		var title, body string
 		if m.mode == modeInput {
 			title = m.inputTitle()
 			m.input.SetWidth(popupWidth - 4)
 			body = m.input.View()
+			if m.isTagTarget() {
+				body += "\n" + m.suggestionLine()
+			}
+			if m.target == targetSetNotes {
+				body += "\n" + descStyle.Render("markdown · enter new line · ctrl+s save · esc cancel")
+			}
+			if m.target == targetExportFile {
+				body += "\n" + descStyle.Render(m.exportHint())
+			}
 		} else {
 			title = "Confirm"
 			body = m.deleteMessage
@@ -177,6 +237,17 @@ func (m Model) View() string {
 	return baseView
 }
 
+func (m Model) suggestionLine() string {
+	suggestions := m.tagSuggestions()
+	if len(suggestions) == 0 {
+		return descStyle.Render("tab completes existing tags")
+	}
+	if len(suggestions) > maxTagSuggestions {
+		suggestions = append(suggestions[:maxTagSuggestions], "…")
+	}
+	return tagStyle.Render(strings.Join(suggestions, "  "))
+}
+
 func renderPopup(width int, title, body string) string {
 	bc := lipgloss.NewStyle().Foreground(focusedBorderColor)
 	border := lipgloss.RoundedBorder()
@@ -299,14 +370,22 @@ func spliceLineAnsi(bgLine, fgLine string, xOff, totalWidth int) string {
 	return prefix.String() + fgLine + suffix.String()
 }
 
-func (m Model) padContent(lines []string, height int) string {
+func (m Model) padContent(lines []string, offset, height, width int) string {
+	if offset > 0 && offset < len(lines) {
+		lines = lines[offset:]
+	}
 	if len(lines) > height {
 		lines = lines[:height]
 	}
-	for len(lines) < height {
-		lines = append(lines, "")
+	out := make([]string, height)
+	for i, line := range lines {
+		out[i] = truncateLine(line, width)
 	}
-	return strings.Join(lines, "\n")
+	return strings.Join(out, "\n")
+}
+
+func truncateLine(line string, width int) string {
+	return ansi.Truncate(line, width, "…")
 }
 
 func (m Model) projectLines() []string {
@@ -314,49 +393,144 @@ func (m Model) projectLines() []string {
 		return []string{normalStyle.Render("No projects yet. Press a to create one.")}
 	}
 
-	lines := make([]string, 0, len(m.store.Projects))
-	for i, p := range m.store.Projects {
-		text := fmt.Sprintf("%s (%d)", p.Name, len(p.Todos))
+	visible := m.visibleProjects()
+	if len(visible) == 0 {
+		return []string{normalStyle.Render(fmt.Sprintf("No matches for %q.", m.search))}
+	}
+
+	lines := make([]string, 0, len(visible))
+	for _, i := range visible {
+		p := m.store.Projects[i]
+		style, prefix := normalStyle, "  "
 		if i == m.projectCursor {
-			lines = append(lines, selectedStyle.Render("▶ "+text))
-		} else {
-			lines = append(lines, normalStyle.Render("  "+text))
+			style, prefix = selectedStyle, "▶ "
 		}
+		positions, _ := fuzzyMatch(m.search, p.Name)
+		count := fmt.Sprintf(" (%d)", model.CountTodos(p.Todos))
+		lines = append(lines, style.Render(prefix)+highlight(p.Name, positions, style)+style.Render(count))
 	}
 	return lines
 }
 
 func (m Model) todoLines() []string {
+	refs := m.visibleTodos()
+	if m.view == viewDueSoon && len(refs) == 0 {
+		return []string{normalStyle.Render("Nothing due in the next week.")}
+	}
+	if m.view == viewDoneThisWeek && len(refs) == 0 {
+		return []string{normalStyle.Render("Nothing completed since Monday yet.")}
+	}
+	if m.view == viewTag && len(refs) == 0 {
+		return []string{normalStyle.Render("No todos tagged #" + m.tagFilter + ".")}
+	}
 	p := m.currentProject()
-	if p == nil {
+	if m.view == viewProject && p == nil {
 		return []string{normalStyle.Render("Select or create a project.")}
 	}
-	if len(p.Todos) == 0 {
+	if len(refs) == 0 && m.search != "" {
+		return []string{normalStyle.Render(fmt.Sprintf("No todos match %q.", m.search))}
+	}
+	if len(refs) == 0 {
 		return []string{normalStyle.Render("No todos yet. Press a to add one.")}
 	}
 
-	lines := make([]string, 0, len(p.Todos))
-	for i, t := range p.Todos {
+	now := time.Now()
+	lines := make([]string, 0, len(refs))
+	for i, ref := range refs {
+		t := m.todoAt(ref)
 		prefix := "  "
 		if i == m.todoCursor {
 			prefix = "▶ "
 		}
+		prefix += strings.Repeat("  ", ref.depth)
 		box := "[ ]"
 		if t.Completed {
 			box = "[x]"
 		}
 
-		text := fmt.Sprintf("%s%s %s", prefix, box, t.Title)
+		var text string
+		if m.view != viewProject {
+			text += " · " + m.store.Project(ref.project).Name
+		}
 		if t.Link != "" {
 			text += " 🔗"
 		}
+		if t.Notes != "" {
+			text += " ✎"
+		}
+
+		style := normalStyle
 		if t.Completed {
-			lines = append(lines, completedStyle.Render(text))
+			style = completedStyle
 		} else if i == m.todoCursor {
-			lines = append(lines, selectedStyle.Render(text))
-		} else {
-			lines = append(lines, normalStyle.Render(text))
+			style = selectedStyle
 		}
+		positions, _ := fuzzyMatch(m.search, t.Title)
+		line := style.Render(prefix+box+" ") + priorityMarker(*t) + highlight(t.Title, positions, style) + style.Render(text)
+		if progress := progressLabel(*t); progress != "" {
+			line += " " + descStyle.Render(progress)
+		}
+		if len(t.Tags) > 0 {
+			line += " " + tagStyle.Render(formatTags(t.Tags))
+		}
+		if label := dueLabel(*t, now); label != "" {
+			line += " " + label
+		}
+		if age := ageLabel(*t, now); age != "" {
+			line += " " + descStyle.Render(age)
+		}
+		lines = append(lines, line)
 	}
 	return lines
 }
+
+func priorityMarker(t model.Todo) string {
+	marker := strings.Repeat("!", t.Priority.Rank())
+	if marker == "" {
+		return ""
+	}
+	marker += " "
+	if t.Completed {
+		return completedStyle.Render(marker)
+	}
+	switch t.Priority {
+	case model.PriorityHigh:
+		return highPriorityStyle.Render(marker)
+	case model.PriorityMedium:
+		return mediumPriorityStyle.Render(marker)
+	default:
+		return lowPriorityStyle.Render(marker)
+	}
+}
+
+func ageLabel(t model.Todo, now time.Time) string {
+	if t.Completed && !t.CompletedAt.IsZero() {
+		return "done " + relativeAge(t.CompletedAt, now)
+	}
+	if !t.Completed && !t.CreatedAt.IsZero() {
+		return "added " + relativeAge(t.CreatedAt, now)
+	}
+	return ""
+}
+
+func dueLabel(t model.Todo, now time.Time) string {
+	days, ok := t.DaysUntilDue(now)
+	if !ok {
+		return ""
+	}
+	if t.Completed {
+		return descStyle.Render(t.Due)
+	}
+	switch {
+	case days < 0:
+		return overdueStyle.Render(fmt.Sprintf("overdue %dd", -days))
+	case days == 0:
+		return dueTodayStyle.Render("due today")
+	case days == 1:
+		return upcomingStyle.Render("due tomorrow")
+	case days <= dueSoonDays:
+		return upcomingStyle.Render(fmt.Sprintf("due in %dd", days))
+	default:
+		return descStyle.Render("due " + t.Due)
+	}
+}
@@ -0,0 +1,95 @@
+package tui
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/danjecu/focusboard-tui/internal/storage"
+)
+
+const watchInterval = 2 * time.Second
+
+type fileCheckMsg struct{}
+
+func watchFile() tea.Cmd {
+	return tea.Tick(watchInterval, func(time.Time) tea.Msg {
+		return fileCheckMsg{}
+	})
+}
+
+// checkFile reloads the board when another program changed the data file.
+// With unsaved local changes the user is asked which side to keep instead.
+func (m *Model) checkFile() {
+	if m.mode != modeNormal {
+		return
+	}
+	stamp, err := storage.StatFile(m.dataPath)
+	if err != nil || stamp.Equal(m.diskStamp) {
+		return
+	}
+	if !stamp.Exists() {
+		m.diskStamp = stamp
+		m.status = fmt.Sprintf("%s was removed; it will be recreated on the next change", filepath.Base(m.dataPath))
+		m.statusErr = true
+		return
+	}
+	if m.dirty {
+		m.mode = modeConfirmConflict
+		return
+	}
+	m.reload("external changes")
+}
+
+func (m *Model) reload(desc string) {
+	s, err := storage.Load(m.dataPath)
+	m.diskStamp, _ = storage.StatFile(m.dataPath)
+	if err != nil {
+		m.status = fmt.Sprintf("reload failed: %v", err)
+		m.statusErr = true
+		return
+	}
+	m.checkpoint(desc)
+	m.store = s
+	m.readOnly = false
+	m.dirty = false
+	m.clampCursors()
+	m.status = fmt.Sprintf("Reloaded %s (changed on disk)", filepath.Base(m.dataPath))
+	m.statusErr = false
+}
+
+func (m Model) handleConflictKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "r":
+		m.mode = modeNormal
+		m.reload("discard local changes")
+	case "k":
+		// A board that failed to load is empty or a recovery leftover;
+		// writing it would clobber the newer or repaired file on disk.
+		if m.readOnly {
+			return m, nil
+		}
+		m.mode = modeNormal
+		m.diskStamp, _ = storage.StatFile(m.dataPath)
+		m.persist()
+		if !m.statusErr {
+			m.status = "Kept local changes and overwrote " + filepath.Base(m.dataPath)
+		}
+	}
+	return m, nil
+}
+
+func (m Model) conflictMessage() string {
+	if m.readOnly {
+		return fmt.Sprintf("%s changed on disk. It failed to load before, so your\n"+
+			"changes cannot be written over it.\n\n"+
+			"r  reload from disk (u brings your changes back)", filepath.Base(m.dataPath))
+	}
+	return fmt.Sprintf("%s changed on disk and you have unsaved changes.\n\n"+
+		"r  reload from disk (u brings your changes back)\n"+
+		"k  keep mine and overwrite the file", filepath.Base(m.dataPath))
+}
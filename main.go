@@ -1,18 +1,42 @@
 package main
 
 import (
+	"errors"
+	"flag"
 	"fmt"
 	"os"
 
 	tea "github.com/charmbracelet/bubbletea"
 
+	"github.com/danjecu/focusboard-tui/internal/cli"
+	"github.com/danjecu/focusboard-tui/internal/storage"
 	"github.com/danjecu/focusboard-tui/internal/tui"
 )
 
-const dataFile = "todos.json"
-
 func main() {
-	m := tui.New(dataFile)
+	fs := flag.NewFlagSet("focusboard", flag.ContinueOnError)
+	file := fs.String("file", "", "path to the data file (overrides $"+storage.EnvFile+")")
+	if err := fs.Parse(os.Args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(cli.ExitOK)
+		}
+		os.Exit(cli.ExitUsage)
+	}
+
+	path, err := storage.ResolvePath(*file)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(cli.ExitError)
+	}
+	if notice := storage.LegacyNotice(path); notice != "" {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", notice)
+	}
+
+	if fs.NArg() > 0 {
+		os.Exit(cli.Run(fs.Args(), path, os.Stdout, os.Stderr))
+	}
+
+	m := tui.New(path)
 	p := tea.NewProgram(m, tea.WithAltScreen())
 	if _, err := p.Run(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)